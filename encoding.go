@@ -0,0 +1,201 @@
+/*
+ * encoding.go
+ * Decode input which isn't UTF-8
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+/* Byte order marks which start UTF-16 text */
+const (
+	bomLE = "\xff\xfe"
+	bomBE = "\xfe\xff"
+)
+
+/* encodings maps the names -encoding takes to functions which return readers
+which decode their input to UTF-8.  With auto, only UTF-16 with a byte order
+mark is decoded. */
+var encodings = map[string]func(r io.Reader) (io.Reader, error){
+	"auto": decodeUTF16,
+	"utf-8": func(r io.Reader) (io.Reader, error) {
+		return r, nil
+	},
+	"utf-16le": func(r io.Reader) (io.Reader, error) {
+		return newUTF16Reader(r, binary.LittleEndian), nil
+	},
+	"utf-16be": func(r io.Reader) (io.Reader, error) {
+		return newUTF16Reader(r, binary.BigEndian), nil
+	},
+	"latin1": func(r io.Reader) (io.Reader, error) {
+		br := bufio.NewReader(r)
+		return &decodeReader{decode: func() (rune, error) {
+			b, err := br.ReadByte()
+			return rune(b), err
+		}}, nil
+	},
+}
+
+/* encodingNames returns the names of the encodings, in order */
+func encodingNames() string {
+	ns := []string{}
+	for n := range encodings {
+		ns = append(ns, n)
+	}
+	sort.Strings(ns)
+	return strings.Join(ns, ", ")
+}
+
+/* utf16Order returns the byte order of the UTF-16 text which b is the start
+of, or nil if b doesn't start with a UTF-16 byte order mark */
+func utf16Order(b []byte) binary.ByteOrder {
+	switch string(b) {
+	case bomLE:
+		return binary.LittleEndian
+	case bomBE:
+		return binary.BigEndian
+	}
+	return nil
+}
+
+/* isUTF16 returns true if r starts with a UTF-16 byte order mark */
+func isUTF16(r io.ReaderAt) bool {
+	bom := make([]byte, len(bomLE))
+	n, _ := r.ReadAt(bom, 0)
+	return nil != utf16Order(bom[:n])
+}
+
+/* decodeUTF16 returns a reader which decodes r to UTF-8, without the byte
+order mark, if r starts with a UTF-16 byte order mark.  Otherwise, r's
+contents are returned unchanged. */
+func decodeUTF16(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	bom, err := br.Peek(len(bomLE))
+	/* Too short to be UTF-16 */
+	if io.EOF == err {
+		return br, nil
+	}
+	if nil != err {
+		return nil, err
+	}
+	order := utf16Order(bom)
+	if nil == order {
+		return br, nil
+	}
+	br.Discard(len(bom))
+	return newUTF16Reader(br, order), nil
+}
+
+/* decodeReader is an io.Reader which returns the UTF-8 encoding of the runes
+returned by decode, until it returns an error */
+type decodeReader struct {
+	decode  func() (rune, error) /* Decodes the next rune */
+	pending []byte               /* Decoded, but not yet read */
+	err     error                /* Error to return once pending is read */
+}
+
+/* Read reads as much decoded text into p as will fit */
+func (d *decodeReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		/* Finish off a rune which didn't fit last time */
+		if 0 != len(d.pending) {
+			c := copy(p[n:], d.pending)
+			d.pending = d.pending[c:]
+			n += c
+			continue
+		}
+		if nil != d.err {
+			break
+		}
+		c, err := d.decode()
+		if nil != err {
+			d.err = err
+			continue
+		}
+		if utf8.UTFMax <= len(p)-n {
+			n += utf8.EncodeRune(p[n:], c)
+		} else {
+			d.pending = utf8.AppendRune(nil, c)
+		}
+	}
+	if 0 == n && nil != d.err {
+		return 0, d.err
+	}
+	return n, nil
+}
+
+/* utf16Decoder decodes UTF-16.  Unpaired surrogates and an odd byte at the
+end become utf8.RuneError. */
+type utf16Decoder struct {
+	r     *bufio.Reader    /* UTF-16 */
+	order binary.ByteOrder /* Byte order of r */
+	next  rune             /* Unit after a lone surrogate, or -1 */
+}
+
+/* newUTF16Reader returns a reader which decodes the UTF-16 in r, which is in
+the given byte order */
+func newUTF16Reader(r io.Reader, order binary.ByteOrder) io.Reader {
+	u := &utf16Decoder{r: bufio.NewReader(r), order: order, next: -1}
+	return &decodeReader{decode: u.rune}
+}
+
+/* rune decodes the next rune, which may take two units */
+func (u *utf16Decoder) rune() (rune, error) {
+	c, err := u.unit()
+	if nil != err || !utf16.IsSurrogate(c) {
+		return c, err
+	}
+	/* A high surrogate needs a low one after it */
+	if 0xdc00 > c {
+		lo, err := u.unit()
+		if nil != err {
+			return utf8.RuneError, nil
+		}
+		if r := utf16.DecodeRune(c, lo); utf8.RuneError != r {
+			return r, nil
+		}
+		u.next = lo
+	}
+	return utf8.RuneError, nil
+}
+
+/* unit reads the next UTF-16 code unit */
+func (u *utf16Decoder) unit() (rune, error) {
+	if -1 != u.next {
+		c := u.next
+		u.next = -1
+		return c, nil
+	}
+	var b [2]byte
+	if _, err := io.ReadFull(u.r, b[:]); nil != err {
+		if io.ErrUnexpectedEOF == err {
+			return utf8.RuneError, nil
+		}
+		return 0, err
+	}
+	return rune(u.order.Uint16(b[:])), nil
+}
@@ -1,9 +1,10 @@
 /*
  * findcc.go
  * Small program to find an n-digit number with a mod-10 checksum in a file
+ * The scanning itself is done by the findcc package
  * by J. Stuart McMurray
  * created 20150115
- * last modified 20150115
+ * last modified 20261014
  * Copyright (c) 2014 J. Stuart McMurray <kd5pbo@gmail.com>
  *
  * Permission to use, copy, modify, and distribute this software for any
@@ -21,12 +22,26 @@
 package main
 
 import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"github.com/joeljunstrom/go-luhn"
+	"github.com/kd5pbo/findcc/findcc"
 	"io"
+	"io/fs"
+	"math"
 	"os"
-	"unicode"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"text/template"
 )
 
 /* Usage statement */
@@ -34,23 +49,232 @@ import (
 func main() { os.Exit(mymain()) }
 func mymain() int {
 	/* Get the number of digits in the number on the command line */
-	numlen := flag.Int("n", 16, "Length of number to find, including "+
-		"the check digit.")
+	var numlens lengths
+	flag.Var(&numlens, "n", "Length of number to find, including the "+
+		"check digit.  May be given more than once to find more than "+
+		"one length.  (default 16, or 10 with -isbn10)")
 	mod10 := flag.Bool("mod10", false, "Use a simple sum modulus 10 "+
 		"instead of the Luhn algorithm.")
 	quiet := flag.Bool("q", false, "Be quiet; don't print the header.")
+	jsonOut := flag.Bool("json", false, "Print each match as a JSON "+
+		"object on its own line.  Implies -q.")
+	recurse := flag.Bool("r", false, "Recursively scan the regular "+
+		"files in directories given as arguments.")
+	format := flag.String("format", "", "Print each match with this "+
+		"Go template, given the fields File, Offset, Line, Column, "+
+		"Number, Raw, Length, Brand, Algorithm, Before and After, "+
+		"instead of as a table.  Ignored with -json and -csv.")
+	nulOut := flag.Bool("0", false, "End each match with a NUL instead "+
+		"of a newline, for xargs -0, with tabs between the fields "+
+		"and no header.")
+	withName := flag.Bool("H", false, "Always print the filename with "+
+		"each match, even if there's only one input.")
+	noName := flag.Bool("h", false, "Never print the filename with "+
+		"each match, even if there's more than one input.")
+	csvOut := flag.Bool("csv", false, "Print matches as CSV, with a "+
+		"header row unless -q is given.")
+	sep := flag.Bool("sep", false, "Allow a single space or dash "+
+		"between the digits of a number.")
+	rawCol := flag.Bool("raw-col", false, "Also print the number as it "+
+		"appeared in the input, including separators.")
+	algoCol := flag.Bool("algo-col", false, "Also print the check "+
+		"digit algorithm each number is valid with, as is done "+
+		"anyway with more than one algorithm.")
+	brand := flag.Bool("brand", false, "Print the card brand of each "+
+		"match.  Only used with the Luhn algorithm.")
+	mask := flag.Bool("mask", false, "Only print the first six and "+
+		"last four digits of each number.")
+	noTest := flag.Bool("no-test", false, "Don't report well-known "+
+		"test card numbers.")
+	noTrivial := flag.Bool("no-trivial", false, "Don't report numbers "+
+		"too regular to be real, such as all one digit, counting up "+
+		"or down, or a few digits repeated.")
+	countOnly := flag.Bool("c", false, "Only print the number of "+
+		"matches in each input.")
+	filesWith := flag.Bool("l", false, "Only print the names of "+
+		"inputs with a match, like grep -l.")
+	filesWithout := flag.Bool("L", false, "Only print the names of "+
+		"inputs without a match, like grep -L.")
+	silent := flag.Bool("s", false, "Print nothing; only set the exit "+
+		"status.")
+	maxCount := flag.Int("m", 0, "Stop after this many matches, if "+
+		"not 0.")
+	noOverlap := flag.Bool("no-overlap", false, "Don't allow matches "+
+		"to overlap.  Numbers starting inside a match are missed.")
+	minLen := flag.Int("min", 0, "Length of the shortest number to "+
+		"find, with -max.  May not be used with -n.")
+	maxLen := flag.Int("max", 0, "Length of the longest number to "+
+		"find, with -min.  May not be used with -n.")
+	colCol := flag.Bool("col", false, "Also print the column, counting "+
+		"characters from 1, in which each number starts.")
+	hexOff := flag.Bool("hex", false, "Print offsets in hexadecimal.")
+	verhoeff := flag.Bool("verhoeff", false, "Use the Verhoeff "+
+		"algorithm instead of the Luhn algorithm.")
+	damm := flag.Bool("damm", false, "Use the Damm algorithm instead "+
+		"of the Luhn algorithm.")
+	isbn10 := flag.Bool("isbn10", false, "Use the ISBN-10 check, with "+
+		"an X allowed as the check digit, instead of the Luhn "+
+		"algorithm.  Implies -n 10 if no length is given.")
+	all := flag.Bool("all", false, "Use the Luhn, -mod10, -verhoeff, "+
+		"-damm and -isbn10 checks all at once, as well as -mod if "+
+		"it's given, printing each number once for each it's valid "+
+		"with.")
+	iban := flag.Bool("iban", false, "Find IBANs, which may contain "+
+		"letters, instead of numbers valid with the Luhn algorithm.  "+
+		"IBANs of every country's length are found if no length is "+
+		"given.")
+	mod := flag.Int("mod", 0, "Use a weighted sum modulus this number "+
+		"instead of the Luhn algorithm, if not 0.  The weighted sum "+
+		"of all of the digits must be a multiple of it.")
+	var modWeights weights
+	flag.Var(&modWeights, "weights", "Comma-separated weights for "+
+		"-mod, used in turn from the check digit leftwards.  "+
+		"(default 1)")
+	alnum := flag.Bool("alnum", false, "Use Luhn mod N over the "+
+		"characters in -alphabet, such as letters as well as digits, "+
+		"instead of the Luhn algorithm.")
+	alphabet := flag.String("alphabet", findcc.DefaultAlphabet, "The "+
+		"characters -alnum uses, each worth its position.  Lowercase "+
+		"letters are also found if only uppercase are given.")
+	leadingCheck := flag.Bool("leading-check", false, "The check "+
+		"digit is the first digit instead of the last, so numbers "+
+		"are checked backwards.  May not be used with -iban.")
+	gz := flag.Bool("z", false, "Decompress input as gzip, even if it "+
+		"doesn't look gzipped.")
+	zipIn := flag.Bool("zip", false, "Treat every file as a zip "+
+		"archive, not just those with names ending in .zip.")
+	follow := flag.Bool("f", false, "Follow the file like tail -f, "+
+		"waiting for more to be written at the end.  Only one file "+
+		"may be given.  Interrupt to stop.")
+	check := flag.String("check", "", "Only check whether this number "+
+		"has a valid check digit, instead of scanning.")
+	gen := flag.String("gen", "", "Only print this number with the "+
+		"check digit which makes it valid added, instead of "+
+		"scanning.")
+	list := flag.Bool("list", false, "Check each line of the input as "+
+		"a number on its own, instead of scanning.  With -q, only "+
+		"the valid numbers are printed.")
+	contextN := flag.Int("context", 0, "Also print this many bytes "+
+		"before and after each number, with unprintable bytes as "+
+		"dots and, with -mask, digits as *s.")
+	afterN := flag.Int("A", 0, "Print this many lines after each line "+
+		"with a match, like grep, instead of the matches themselves.")
+	beforeN := flag.Int("B", 0, "Print this many lines before each "+
+		"line with a match, like grep, instead of the matches "+
+		"themselves.")
+	aroundN := flag.Int("C", 0, "Like -A and -B together.")
+	color := flag.String("color", "never", "Highlight numbers in the "+
+		"table: always, never, or auto, when printing to a terminal.")
+	outName := flag.String("o", "", "Write the matches to this file "+
+		"instead of the standard output.")
+	jobs := flag.Int("j", 1, "Scan this many files at once.  Output is "+
+		"in the same order as if they were scanned one at a time.")
+	split := flag.Int("split", 1, "Split each regular file into this "+
+		"many pieces, at newlines, and scan them at once.")
+	mmapIn := flag.Bool("mmap", false, "Memory-map regular files "+
+		"instead of reading them, where possible.")
+	var rangeStart, rangeEnd byteSize
+	flag.Var(&rangeStart, "start", "Start scanning each file this many "+
+		"bytes in, which may end in K, M, G or T.  Offsets are still "+
+		"from the start of the file, but lines are counted from here.")
+	flag.Var(&rangeEnd, "end", "Stop scanning each file this many "+
+		"bytes in, which may end in K, M, G or T, if not 0.")
+	timeout := flag.Duration("timeout", 0, "Stop scanning after this "+
+		"long, and exit with -8.")
+	unique := flag.Bool("unique", false, "Only print the first match "+
+		"of each number, wherever it's found.")
+	uniqueMax := flag.Int("unique-max", 0, "With -unique, stop "+
+		"removing duplicates, with a warning, once this many "+
+		"different numbers have been seen, if not 0.")
+	var maxSize byteSize
+	flag.Var(&maxSize, "max-size", "Skip files bigger than this, which "+
+		"may end in K, M, G or T for KB, MB, GB or TB, if not 0.  "+
+		"The standard input and pipes are never skipped.")
+	encoding := flag.String("encoding", "auto", "Decode input from "+
+		"this encoding before scanning: "+encodingNames()+".  With "+
+		"auto, UTF-16 with a byte order mark is decoded, and "+
+		"anything else is scanned as it is.")
+	textOnly := flag.Bool("text", false, "Skip files which look "+
+		"binary, with a NUL or many bytes which aren't text near the "+
+		"start.")
+	statsOut := flag.Bool("stats", false, "When done, print how much "+
+		"was scanned and how many matches of each brand, with "+
+		"-brand, or length were found to the standard error.")
 	/* Usage statement */
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: %s [-q] [-n NN] [filename]",
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] [filename...]",
 			os.Args[0])
 		fmt.Fprintf(os.Stderr, `
 
-Search for sequences of a set number of ascii digits (controllable by -n) that
-either passes validation with the Luhn algorithm or has a final digit that is
-equal to the modulus 10 sum of the other digits (with -mod10).  If no filename
-is given, the standard input is used.  The offset in the file and line number
-where the number was found, as well as the number with its check digit are
-printed in a tabular format, separated by whitespace.
+Search for sequences of a set number of decimal digits (controllable by -n), in
+any script, that passes validation with the Luhn algorithm, or with another
+check digit algorithm: a final digit equal to the modulus 10 sum of the other
+digits (with -mod10), Verhoeff's algorithm (with -verhoeff), Damm's algorithm
+(with -damm), ISBN-10's (with -isbn10) or a weighted sum modulus any number
+(with -mod and -weights), so -mod 10 -weights 1,7,3 -n 9 finds ABA routing
+numbers.  With -iban, IBANs are found instead, letters and all.  With -alnum,
+Luhn mod N is used instead, over the digits and uppercase letters or the
+characters given with -alphabet, each worth its position, so product keys and
+coupon codes made of letters and digits may be found.  More than one algorithm
+may be given, and -all uses Luhn's, -mod10, -verhoeff, -damm and -isbn10
+together; a number valid with more than one of them is printed once for each,
+with the algorithm, after any shorter number ending on the same digit and in
+the order they're listed here.  With -check, a single number is checked instead
+of scanning, and valid or invalid is printed, and with -gen, the check digit
+which makes a number valid is added to it.  With -list, each line of the input
+is checked as a number on its own, and a count of the valid ones is printed at
+the end.  If no filename is given, the standard input is used.  Gzipped input
+is decompressed, and offsets are into the decompressed data; -z decompresses
+input even if it doesn't look gzipped.  Likewise, input starting with a UTF-16
+byte order mark is decoded, and offsets are into the text as UTF-8, so they're
+rune positions as long as it's ASCII; columns always count decoded characters.
+-encoding decodes input from latin1, utf-16le or utf-16be instead, or with
+utf-8 turns decoding off.  Each file in a zip archive (a file whose name ends
+in .zip, or any file with -zip) is scanned separately, and matches are prefixed
+with the archive's name and the file's.  If more than one filename is given,
+each is scanned in turn and matches are prefixed with the filename; -H always
+prefixes them with the filename, using (standard input) for the standard input,
+and -h never does.  In JSON and CSV, the filename is the file field.  With -j,
+several files are scanned at once, and with -split, pieces of one big file are,
+without changing the output.  With -r, directories are walked and every regular
+file in them is scanned; symbolic links are not followed.  With -start and
+-end, only that range of bytes of each file is scanned, as stored rather than
+decompressed; offsets are still from the start of the file, but lines and
+columns are counted from the start of the range, and input which can't be
+seeked, such as a pipe, is an error.  With -text, files which look binary, with
+a NUL byte or many bytes which aren't text in the first 8KB, are skipped, and
+with -max-size, so are files bigger than a size such as 10M or 1G.  With -f,
+findcc waits for more to be written to the end of the file, and starts again at
+the beginning if the file is truncated or replaced, as when logs are rotated;
+offsets then count everything read so far.  An interrupt stops it.  With -sep,
+numbers may be broken up by single spaces or dashes, as in
+4111-1111-1111-1111.  The byte offset in the file (counting from 0) of the
+first digit and line number where the number was found, as well as the number
+with its check digit are printed in a tabular format, separated by whitespace,
+as one JSON object per line (with -json), as CSV (with -csv), or as records
+ending in a NUL byte, with a tab between each field and no header, for xargs -0
+(with -0).  With -format, each match is instead printed with a Go template,
+such as '{{.Line}}:{{.Offset}}:{{.Number}}', which may use the fields File,
+Offset, Line, Column, Number, Raw, Length, Brand, Algorithm, Before and After;
+-json and -csv take precedence over it.  With -col, the column in which the
+number starts is also printed, counting characters from 1.  Lines end with a
+newline, a carriage return, or both, as on Windows, which is only one line
+break.  With -context, the bytes either side of each number are printed as
+well.  With -A, -B or -C, lines with matches and the lines around them are
+printed instead, like grep, with line numbers counting from 0 as usual; when
+two matches are close together their lines are printed once, as one group.
+With -unique, each number is only printed the first time it's found, so the
+offset and file printed are where it was first seen; -unique-max puts a limit
+on how many numbers are remembered.  With -stats, a line saying how many bytes
+were scanned and how many matches, different numbers, and numbers of each
+length (or brand, with -brand) were found is printed to the standard error at
+the end.  With -l, only the names of inputs with a match are printed, and each
+input is only scanned as far as its first match; with -L, only the names of
+those without one are.  The exit status is 0 if a number was found, 1 if not,
+and negative if there was an error.  Unless following a file, an interrupt
+prints any matches still buffered and how much was scanned, and findcc exits
+with -7.  With -timeout, findcc stops the same way once the time is up, but
+exits with -8; whatever was printed before then is still correct.
 
 Options:
 `)
@@ -58,91 +282,1062 @@ Options:
 	}
 	flag.Parse()
 
-	/* Work out where to get input */
-	input := os.Stdin /* Default to stdin */
-	/* Open a file if specified */
-	if 1 == flag.NArg() {
-		var err error
-		if input, err = os.Open(flag.Arg(0)); nil != err {
-			fmt.Fprintf(os.Stderr, "Unable to open %v: %v",
-				flag.Arg(0), err)
+	/* Work out the lengths to find.  A range of lengths replaces -n, and
+	either end of it may be left off for just one length. */
+	lens := []int(numlens)
+	/* The scanner knows how long IBANs are, so leave their lengths
+	unset */
+	if 0 == len(lens) && *isbn10 {
+		lens = []int{10}
+	} else if 0 == len(lens) && !*iban {
+		lens = []int{16}
+	}
+	if 0 != *minLen || 0 != *maxLen {
+		if 0 != len(numlens) {
+			fmt.Fprintf(os.Stderr, "-n may not be used with -min "+
+				"or -max.\n")
+			return -2
+		}
+		if 0 == *minLen {
+			*minLen = *maxLen
+		} else if 0 == *maxLen {
+			*maxLen = *minLen
+		}
+		if *minLen > *maxLen {
+			fmt.Fprintf(os.Stderr, "-min may not be more than "+
+				"-max.\n")
+			return -2
+		}
+		lens = []int{}
+		for l := *minLen; l <= *maxLen; l++ {
+			lens = append(lens, l)
+		}
+	}
+	/* Lines of context replace the usual output */
+	if 0 < *aroundN && 0 == *afterN {
+		*afterN = *aroundN
+	}
+	if 0 < *aroundN && 0 == *beforeN {
+		*beforeN = *aroundN
+	}
+	lineCtx := (0 < *afterN || 0 < *beforeN) && !*countOnly
+	if lineCtx && (*jsonOut || *csvOut || *nulOut) {
+		fmt.Fprintf(os.Stderr, "-A, -B and -C may not be used with "+
+			"-json, -csv or -0.\n")
+		return -2
+	}
+	listNames := *filesWith || *filesWithout /* -l or -L */
+	if listNames && (*filesWith && *filesWithout || *countOnly ||
+		*list || lineCtx || *jsonOut || *csvOut) {
+		fmt.Fprintf(os.Stderr, "Only one of -l and -L may be given, "+
+			"and not with -c, -list, -A, -B, -C, -json or -csv.\n")
+		return -2
+	}
+	if *nulOut && (*jsonOut || *csvOut) {
+		fmt.Fprintf(os.Stderr, "-0 may not be used with -json or "+
+			"-csv.\n")
+		return -2
+	}
+	if *unique && 1 < *jobs {
+		/* Which file a number's first found in would be down to
+		chance */
+		fmt.Fprintf(os.Stderr, "-unique may not be used with -j.\n")
+		return -2
+	}
+	enc := strings.ToLower(*encoding)
+	decode, ok := encodings[enc]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown encoding %q.  Supported "+
+			"encodings are %v.\n", *encoding, encodingNames())
+		return -2
+	}
+	if 0 != rangeEnd && rangeEnd < rangeStart {
+		fmt.Fprintf(os.Stderr, "-end may not be before -start.\n")
+		return -2
+	}
+	if (0 != rangeStart || 0 != rangeEnd) && (*gz || *follow ||
+		"auto" != enc) {
+		fmt.Fprintf(os.Stderr, "-start and -end may not be used with "+
+			"-z, -f or -encoding.\n")
+		return -2
+	}
+	if 0 > *timeout {
+		fmt.Fprintf(os.Stderr, "-timeout may not be negative.\n")
+		return -2
+	}
+	if 0 != len(lens) && 1 > lens[0] {
+		fmt.Fprintf(os.Stderr, "Length must be at least 1.\n")
+		return -2
+	}
+
+	/* Work out which check digit algorithms to use.  More than one
+	may be given, and -all uses every one which doesn't need telling
+	more. */
+	var algs []findcc.Algorithm
+	for _, a := range []struct {
+		use bool
+		alg findcc.Algorithm
+	}{
+		{*all, findcc.Luhn},
+		{*mod10 || *all, findcc.Mod10},
+		{*verhoeff || *all, findcc.Verhoeff},
+		{*damm || *all, findcc.Damm},
+		{*isbn10 || *all, findcc.ISBN10},
+		{*iban, findcc.IBAN},
+		{0 != *mod, findcc.ModN},
+		{*alnum, findcc.LuhnModN},
+	} {
+		if a.use {
+			algs = append(algs, a.alg)
+		}
+	}
+	if 0 == len(algs) {
+		algs = []findcc.Algorithm{findcc.Luhn}
+	}
+	if *iban && *leadingCheck {
+		fmt.Fprintf(os.Stderr, "-leading-check may not be used with "+
+			"-iban.\n")
+		return -2
+	}
+	if (*iban || *alnum) && 1 < len(algs) {
+		fmt.Fprintf(os.Stderr, "-iban and -alnum may not be used with "+
+			"other algorithms.\n")
+		return -2
+	}
+	/* Brands only mean anything for cards */
+	luhnOnly := 1 == len(algs) && findcc.Luhn == algs[0]
+	if 0 != *mod && 2 > *mod {
+		fmt.Fprintf(os.Stderr, "Modulus must be at least 2.\n")
+		return -2
+	}
+	if 0 != len(modWeights) && 0 == *mod {
+		fmt.Fprintf(os.Stderr, "-weights may only be used with "+
+			"-mod.\n")
+		return -2
+	}
+	if findcc.DefaultAlphabet != *alphabet && !*alnum {
+		fmt.Fprintf(os.Stderr, "-alphabet may only be used with "+
+			"-alnum.\n")
+		return -2
+	}
+	/* Scanner to find the numbers */
+	scanner := &findcc.Scanner{
+		Lens:         lens,
+		Algorithms:   algs,
+		SkipTest:     *noTest,
+		SkipTrivial:  *noTrivial,
+		NoOverlap:    *noOverlap,
+		Mod:          *mod,
+		Weights:      modWeights,
+		Alphabet:     *alphabet,
+		LeadingCheck: *leadingCheck,
+		Context:      *contextN,
+	}
+	if *sep {
+		scanner.Seps = " -"
+	}
+
+	/* Work out a check digit if asked, instead of scanning */
+	if "" != *gen {
+		c, err := scanner.CheckDigit(*gen)
+		if nil != err {
+			fmt.Fprintf(os.Stderr, "Unable to generate a check "+
+				"digit for %q: %v\n", *gen, err)
+			return -2
+		}
+		if *leadingCheck {
+			fmt.Printf("%c%v\n", c, *gen)
+		} else {
+			fmt.Printf("%v%c\n", *gen, c)
+		}
+		return 0
+	}
+	/* Check a single number if asked, instead of scanning */
+	if "" != *check {
+		ok, err := scanner.Valid(*check)
+		if nil != err {
+			fmt.Fprintf(os.Stderr, "Unable to check %q: %v\n",
+				*check, err)
+			return -2
+		}
+		if !ok {
+			if !*silent {
+				fmt.Printf("invalid\n")
+			}
+			return 1
+		}
+		if !*silent {
+			fmt.Printf("valid\n")
+		}
+		return 0
+	}
+
+	/* Work out where to send the report.  A file is buffered, unless
+	we're following, in which case nothing would appear until the end. */
+	ofile := os.Stdout
+	if "" != *outName {
+		f, err := os.Create(*outName)
+		if nil != err {
+			fmt.Fprintf(os.Stderr, "Unable to create %v: %v\n",
+				*outName, err)
 			return -1
 		}
-	} else if 1 < flag.NArg() {
-		fmt.Fprintf(os.Stderr, "Multiple input files are not "+
-			"supported.\n")
+		defer f.Close()
+		ofile = f
+	}
+	cout := bufio.NewWriter(ofile) /* Buffered output */
+	out := io.Writer(ofile)        /* Output */
+	if "" != *outName && !*follow {
+		out = cout
+	}
+
+	/* Work out where to get input.  Filenames are only printed if there's
+	more than one, or if we're recursing into directories. */
+	names := flag.Args()
+	if *zipIn && 0 == len(names) {
+		fmt.Fprintf(os.Stderr, "-zip needs a filename.\n")
 		return -2
 	}
+	if *follow && (1 != len(names) || *recurse || *zipIn || *textOnly) {
+		fmt.Fprintf(os.Stderr, "-f needs exactly one filename, and "+
+			"may not be used with -r, -zip or -text.\n")
+		return -2
+	}
+	if *withName && *noName {
+		fmt.Fprintf(os.Stderr, "Only one of -H and -h may be given.\n")
+		return -2
+	}
+	/* isZip returns true if the named file should be treated as a zip
+	archive */
+	isZip := func(name string) bool {
+		return *zipIn || strings.HasSuffix(strings.ToLower(name), ".zip")
+	}
+	anyZip := false /* At least one zip archive given */
+	for _, name := range names {
+		if isZip(name) {
+			anyZip = true
+		}
+	}
 
-	/* Print the header if we're not quiet */
-	if !*quiet {
-		fmt.Printf("OFFSET  LINE  NUMBER\n")
+	/* Work out what to print */
+	showName := (1 < len(names) || *recurse || anyZip || *withName) &&
+		!*noName
+	p := &printer{
+		w:        out,
+		json:     *jsonOut,
+		csv:      *csvOut && !*jsonOut,
+		nul:      *nulOut,
+		mask:     *mask,
+		showName: showName,
+		cols: []column{{
+			name:  "offset",
+			width: 6,
+			val:   func(m findcc.Match) interface{} { return m.Offset },
+		}, {
+			name:  "line",
+			width: 4,
+			val:   func(m findcc.Match) interface{} { return m.Line },
+		}},
+	}
+	/* A template is checked once here, with a made-up match, so a
+	bad one's caught before any scanning */
+	if "" != *format && !p.json && !p.csv {
+		t, err := template.New("format").Parse(*format)
+		if nil == err {
+			err = t.Execute(io.Discard, newFormatRecord(
+				"", findcc.Match{}, false,
+			))
+		}
+		if nil != err {
+			fmt.Fprintf(os.Stderr, "Invalid -format template: %v\n",
+				err)
+			return -2
+		}
+		p.tmpl = t
+	}
+	/* Hex offsets are strings, as JSON has no hex numbers */
+	if *hexOff {
+		p.cols[0].width = 8
+		p.cols[0].val = func(m findcc.Match) interface{} {
+			return fmt.Sprintf("%#x", m.Offset)
+		}
+	}
+	if *colCol {
+		p.cols = append(p.cols, column{
+			name:  "column",
+			width: 6,
+			val:   func(m findcc.Match) interface{} { return m.Column },
+		})
+	}
+	/* Context goes either side of the number */
+	if 0 < *contextN {
+		p.cols = append(p.cols, column{
+			name:  "before",
+			width: *contextN,
+			val: func(m findcc.Match) interface{} {
+				return printable(m.Before, *mask)
+			},
+		})
+	}
+	switch *color {
+	case "always":
+		p.color = true
+	case "never":
+	case "auto":
+		p.color = isTerminal(ofile)
+	default:
+		fmt.Fprintf(os.Stderr, "-color must be always, never or "+
+			"auto.\n")
+		return -2
+	}
+	p.cols = append(p.cols, column{
+		name:  "number",
+		color: true,
+		val: func(m findcc.Match) interface{} {
+			if *mask {
+				return findcc.Mask(m.Number)
+			}
+			return m.Number
+		},
+	})
+	if 0 < *contextN {
+		p.cols = append(p.cols, column{
+			name: "after",
+			val: func(m findcc.Match) interface{} {
+				return printable(m.After, *mask)
+			},
+		})
+	}
+	/* Buffered output would never appear while following */
+	if p.csv && !*follow {
+		p.w = cout
+	}
+	/* No lengths means every IBAN length */
+	if 1 != len(lens) {
+		p.cols = append(p.cols, column{
+			name:  "length",
+			width: 6,
+			val: func(m findcc.Match) interface{} {
+				return len(m.Number)
+			},
+		})
+	}
+	if *brand && luhnOnly {
+		p.cols = append(p.cols, column{
+			name: "brand",
+			val: func(m findcc.Match) interface{} {
+				return findcc.Brand(m.Number)
+			},
+		})
+	}
+	if *rawCol {
+		p.cols = append(p.cols, column{
+			name: "raw",
+			val: func(m findcc.Match) interface{} {
+				if *mask {
+					return findcc.Mask(m.Raw)
+				}
+				return m.Raw
+			},
+		})
+	}
+	/* The algorithm's always in JSON, and elsewhere if asked or if
+	there's more than one */
+	p.cols = append(p.cols, column{
+		name:     "algorithm",
+		width:    len("algorithm"),
+		jsonOnly: !*algoCol && 1 == len(algs),
+		val: func(m findcc.Match) interface{} {
+			return m.Algorithm.String()
+		},
+	})
+
+	/* Print the header if we're not quiet or just counting */
+	if !*quiet && !*countOnly && !*silent && !*list && !lineCtx &&
+		!listNames {
+		if err := p.header(); nil != err {
+			fmt.Fprintf(os.Stderr, "Write error: %v\n", err)
+			return -6
+		}
+	}
+
+	/* Give up after -timeout, if it's set */
+	ctx := context.Background()
+	if 0 != *timeout {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
 	}
 
-	digits := []byte{}  /* Slice to buffer sequential input digits */
-	buf := []byte{0x00} /* Read buffer */
-	nline := 0          /* Number of newlines read */
-	nread := 0          /* Number of bytes read */
-	/* Read until EOF */
-	for {
-		/* Read a byte */
-		if n, err := input.Read(buf); nil != err {
-			/* Don't whine if we've reached EOF */
+	/* scan reads input until EOF and prints the matches it finds, or
+	how many there were with -c, prefixed with name if there's more
+	than one input */
+	found := false       /* Found at least one match */
+	done := false        /* No need to scan any more */
+	total := 0           /* Number of matches in all inputs */
+	var nbytes int64     /* Number of bytes scanned, updated atomically */
+	var outMu sync.Mutex /* Held while printing and counting matches */
+	timedOut := false    /* Ran out of time */
+	/* Counts of what's been found, with -stats */
+	var st *stats
+	if *statsOut {
+		st = newStats(*brand && luhnOnly)
+	}
+	/* Numbers already found, with -unique */
+	var seen map[string]struct{}
+	if *unique {
+		seen = make(map[string]struct{})
+	}
+	/* expire notes that we've run out of time */
+	expire := func() int {
+		outMu.Lock()
+		defer outMu.Unlock()
+		timedOut, done = true, true
+		return -8
+	}
+	/* checkList checks each line of input as a number on its own, for
+	-list.  Blank lines are skipped. */
+	nlist := 0 /* Lines checked */
+	checkList := func(input io.Reader, name string, w io.Writer) int {
+		br := bufio.NewReader(input)
+		for {
+			if nil != ctx.Err() {
+				return expire()
+			}
+			line, err := br.ReadString('\n')
+			if l := strings.TrimSpace(line); "" != l {
+				/* Anything which isn't a number is invalid */
+				ok, verr := scanner.Valid(l)
+				ok = ok && nil == verr
+				outMu.Lock()
+				nlist++
+				if ok {
+					total++
+					found = true
+				}
+				var werr error
+				res, pre := "invalid", ""
+				if ok {
+					res = "valid"
+				}
+				if p.showName {
+					pre = name + ":"
+				}
+				switch {
+				case *silent && ok:
+					done = true
+				case *silent:
+				case *quiet && ok:
+					_, werr = fmt.Fprintf(w, "%v%v\n",
+						pre, l)
+				case !*quiet:
+					_, werr = fmt.Fprintf(w,
+						"%v%-7v  %v\n", pre, res, l)
+				}
+				outMu.Unlock()
+				if nil != werr {
+					fmt.Fprintf(os.Stderr, "Write error: "+
+						"%v\n", werr)
+					return -6
+				}
+				if done {
+					return 0
+				}
+			}
 			if io.EOF == err {
 				return 0
 			}
-			/* Print any other errors, though */
-			fmt.Fprintf(os.Stderr, "Read error: %v\n", err)
-			return -3
-		} else if 0 == n && nil == err {
+			if nil != err {
+				fmt.Fprintf(os.Stderr, "Read error in %v: "+
+					"%v\n", name, err)
+				return -3
+			}
+		}
+	}
+	scan := func(input io.Reader, name string, w io.Writer) int {
+		var werr error /* Error reporting a match */
+		n := 0         /* Number of matches */
+		fp := *p       /* Printer for w */
+		fp.w = w
+		/* With -start or -end, only part of the input is scanned.
+		It's the part as stored, so it's not decompressed. */
+		ranged := 0 != rangeStart || 0 != rangeEnd
+		/* asStored returns true if f is scanned as it's stored,
+		without decompressing or decoding it */
+		asStored := func(f *os.File) bool {
+			switch {
+			case ranged:
+				return true
+			case gzipped(f):
+				return false
+			case "auto" == enc:
+				return !isUTF16(f)
+			}
+			return "utf-8" == enc
+		}
+		/* A regular file can be split into pieces or memory-mapped,
+		as long as we don't need to see it all in order */
+		var whole *os.File         /* File to split or map */
+		var part *io.SectionReader /* Part of whole to scan */
+		var start, size int64      /* Where part is in whole */
+		if f, ok := input.(*os.File); ok && (1 < *split || *mmapIn) &&
+			!*gz && !*list && !lineCtx {
+			if fi, err := f.Stat(); nil == err &&
+				fi.Mode().IsRegular() && asStored(f) {
+				whole = f
+				end := fi.Size()
+				start = int64(rangeStart)
+				if 0 != rangeEnd && int64(rangeEnd) < end {
+					end = int64(rangeEnd)
+				}
+				if start > end {
+					start = end
+				}
+				part = io.NewSectionReader(f, start, end-start)
+				size = part.Size()
+			}
+		}
+		/* A mapped file is read straight from memory.  If it can't
+		be mapped, it's read as usual. */
+		var mem *bytes.Reader /* Mapped part of the file */
+		if nil != whole && *mmapIn {
+			b, unmap, err := mmapFile(whole, start+size)
+			if nil == err {
+				defer unmap()
+				mem = bytes.NewReader(b[start:])
+			}
+		}
+		if nil == mem && 1 >= *split {
+			whole = nil
+		}
+		var err error
+		/* Anything else has to be read from the start of the range */
+		if nil == whole && ranged {
+			sk, ok := input.(io.Seeker)
+			err = errors.New("not seekable")
+			if ok {
+				_, err = sk.Seek(int64(rangeStart), io.SeekStart)
+			}
+			if nil != err {
+				fmt.Fprintf(os.Stderr, "Unable to seek to %v in "+
+					"%v: %v\n", int64(rangeStart), name, err)
+				return -3
+			}
+			if 0 != rangeEnd {
+				input = io.LimitReader(input,
+					int64(rangeEnd-rangeStart))
+			}
+		}
+		/* Binary files are skipped quietly, like grep -I */
+		if nil != whole && *textOnly {
+			b := make([]byte, binaryProbe)
+			n, err := part.ReadAt(b, 0)
+			if nil != err && io.EOF != err {
+				fmt.Fprintf(os.Stderr, "Read error in %v: %v\n",
+					name, err)
+				return -3
+			}
+			if isBinary(b[:n]) {
+				return 0
+			}
+		}
+		var lc *lineContext /* Prints lines around matches */
+		if nil == whole {
+			/* Decompress gzipped input, and decode it */
+			if !ranged {
+				input, err = gunzip(input, *gz)
+			}
+			if !ranged && nil == err {
+				input, err = decode(input)
+			}
+			if nil != err {
+				fmt.Fprintf(os.Stderr, "Read error in %v: %v\n",
+					name, err)
+				return -3
+			}
+			if *textOnly {
+				var text bool
+				input, text, err = probeText(input)
+				if nil != err {
+					fmt.Fprintf(os.Stderr, "Read error in "+
+						"%v: %v\n", name, err)
+					return -3
+				}
+				if !text {
+					return 0
+				}
+			}
+			input = &countReader{r: input, n: &nbytes}
+			if *list {
+				return checkList(input, name, w)
+			}
+			/* Lines around the matches are printed as we go */
+			if lineCtx {
+				pre := ""
+				if p.showName {
+					pre = name + ":"
+				}
+				lc = newLineContext(input, w, &outMu,
+					*beforeN, *afterN, *mask, pre)
+				input = lc
+			}
+		}
+		cb := func(m findcc.Match) error {
+			outMu.Lock()
+			defer outMu.Unlock()
+			/* Another input may have found enough */
+			if done {
+				return errStop
+			}
+			/* Only the first of each number counts, until there
+			are too many to remember */
+			if nil != seen {
+				if _, ok := seen[m.Number]; ok {
+					return nil
+				}
+				if 0 < *uniqueMax && len(seen) >= *uniqueMax {
+					fmt.Fprintf(os.Stderr, "Seen %v "+
+						"different numbers, no longer "+
+						"removing duplicates.\n",
+						len(seen))
+					seen = nil
+				} else {
+					seen[m.Number] = struct{}{}
+				}
+			}
+			n++
+			total++
+			found = true
+			if nil != st {
+				st.add(m)
+			}
+			/* One match is enough to know the exit status */
+			if *silent {
+				done = true
+				return errStop
+			}
+			/* Or to know whether to list the input */
+			if listNames {
+				return errStop
+			}
+			/* Offsets are from the start of the file, even with
+			-start */
+			if nil != lc {
+				lc.match(m)
+			} else if !*countOnly {
+				m.Offset += int(rangeStart)
+				if werr = fp.print(name, m); nil != werr {
+					return werr
+				}
+			}
+			/* Stop if we've found enough */
+			if 0 < *maxCount && total >= *maxCount {
+				done = true
+				return errStop
+			}
+			return nil
+		}
+		switch {
+		case nil != whole && 1 < *split:
+			var ra io.ReaderAt = part
+			if nil != mem {
+				ra = mem
+			}
+			err = scanner.ScanParallel(ctx, ra, size, *split, cb)
+			atomic.AddInt64(&nbytes, size)
+		case nil != mem:
+			err = scanner.ScanContext(ctx, mem, cb)
+			atomic.AddInt64(&nbytes, size-int64(mem.Len()))
+		default:
+			err = scanner.ScanContext(ctx, input, cb)
+		}
+		if errStop == err {
+			err = nil
+		}
+		/* What's been printed so far is still good if we ran out of
+		time */
+		expired := context.DeadlineExceeded == err
+		if expired {
+			err = nil
+		}
+		/* Print whatever lines are left */
+		if nil != lc {
+			outMu.Lock()
+			lc.decide(math.MaxInt)
+			outMu.Unlock()
+			if werr = lc.err; nil != werr && nil == err {
+				err = werr
+			}
+		}
+		/* List the input like grep -l or -L.  It's not known if
+		there'd have been a match if we ran out of time. */
+		if !*silent && nil == err && (*filesWith && 0 < n ||
+			*filesWithout && 0 == n && !expired) {
+			end := "\n"
+			if *nulOut {
+				end = "\x00"
+			}
+			fmt.Fprintf(w, "%v%v", name, end)
+		}
+		/* Print the count like grep -c */
+		if *countOnly && !*silent && nil == werr {
+			if p.showName {
+				fmt.Fprintf(w, "%v:", name)
+			}
+			fmt.Fprintf(w, "%v\n", n)
+		}
+		switch {
+		case nil == err && expired:
+			return expire()
+		case nil == err:
+			return 0
+		case nil != werr:
+			fmt.Fprintf(os.Stderr, "Write error: %v\n", err)
+			return -6
+		case io.ErrNoProgress == err:
 			/* Didn't read anything, but no error?  Probably a bug
 			somewhere else. */
-			fmt.Fprintf(os.Stderr, "Didn't read anything, but "+
-				"no error detected.  This shouldn't happen.")
+			fmt.Fprintf(os.Stderr, "Didn't read anything, but no "+
+				"error detected.  This shouldn't happen.\n")
 			return -4
 		}
-		/* Note how many bytes we've read */
-		nread++
-		/* Note if it's a newline */
-		if '\n' == buf[0] {
-			nline++
+		/* Print any other errors, though */
+		fmt.Fprintf(os.Stderr, "Read error in %v: %v\n", name, err)
+		return -3
+	}
+
+	/* An interrupt stops waiting for more input when following, as does
+	running out of time.  Otherwise, it flushes what's been found so far,
+	says how far we got and exits. */
+	stop := make(chan struct{}) /* Closed to stop following */
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	finished := make(chan struct{}) /* Closed when mymain returns */
+	defer close(finished)
+	var expiry <-chan struct{} /* Closed when -f runs out of time */
+	if *follow {
+		expiry = ctx.Done()
+	}
+	go func() {
+		select {
+		case <-finished:
+			return
+		case <-expiry:
+			expire()
+			close(stop)
+			return
+		case <-sigs:
+		}
+		/* Another interrupt kills us */
+		if *follow {
+			signal.Stop(sigs)
+			close(stop)
+			return
+		}
+		/* Holding the lock stops any more matches being printed */
+		outMu.Lock()
+		cout.Flush()
+		if nil != st {
+			st.print(os.Stderr, atomic.LoadInt64(&nbytes))
+		}
+		fmt.Fprintf(os.Stderr, "Interrupted after scanning %v bytes "+
+			"and finding %v matches.\n", atomic.LoadInt64(&nbytes),
+			total)
+		os.Exit(-7)
+	}()
+
+	/* Default to stdin */
+	ret := 0
+	if 0 == len(names) {
+		ret = scan(os.Stdin, "(standard input)", p.w)
+	}
+	/* scanZip scans each file in the named zip archive, carrying on if
+	one can't be opened.  Matches are prefixed with the name of the
+	archive and the file in it. */
+	scanZip := func(name string, w io.Writer) int {
+		zr, err := zip.OpenReader(name)
+		if nil != err {
+			fmt.Fprintf(os.Stderr, "Unable to open %v: %v\n",
+				name, err)
+			return -1
+		}
+		defer zr.Close()
+		ret := 0
+		for _, f := range zr.File {
+			if done {
+				break
+			}
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			input, err := f.Open()
+			if nil != err {
+				fmt.Fprintf(os.Stderr, "Unable to open %v in "+
+					"%v: %v\n", f.Name, name, err)
+				ret = -1
+				continue
+			}
+			r := scan(input, name+":"+f.Name, w)
+			input.Close()
+			if 0 != r {
+				ret = r
+			}
+		}
+		return ret
+	}
+	/* scanFile scans the named file, printing to w */
+	scanFile := func(name string, w io.Writer) int {
+		/* Don't bother with files which are too big.  Anything
+		without a size is always scanned. */
+		if fi, err := os.Stat(name); 0 != maxSize && nil == err &&
+			fi.Mode().IsRegular() && fi.Size() > int64(maxSize) {
+			fmt.Fprintf(os.Stderr, "Skipping %v: %v bytes is more "+
+				"than -max-size\n", name, fi.Size())
+			return 0
+		}
+		if isZip(name) {
+			return scanZip(name, w)
+		}
+		input, err := os.Open(name)
+		if nil != err {
+			fmt.Fprintf(os.Stderr, "Unable to open %v: %v\n",
+				name, err)
+			return -1
+		}
+		/* The follower closes whichever file it has open */
+		if *follow {
+			fr := &follower{name: name, f: input, stop: stop}
+			defer fr.Close()
+			return scan(fr, name, w)
+		}
+		defer input.Close()
+		return scan(input, name, w)
+	}
+	/* visit scans the named file, or with -j queues it to be scanned */
+	var queue []string /* Files to scan in parallel */
+	visit := func(name string) int {
+		if 1 >= *jobs {
+			return scanFile(name, p.w)
 		}
-		/* If it's not a digit, clear any waiting digits, try again */
-		if !unicode.IsDigit(rune(buf[0])) {
-			if 0 < len(digits) {
-				digits = []byte{}
+		queue = append(queue, name)
+		return 0
+	}
+	/* Scan each file in turn, carrying on if one can't be opened */
+	for _, name := range names {
+		if done {
+			break
+		}
+		/* Walk directories if we're recursing.  WalkDir doesn't
+		follow symlinks, so a link loop can't send us around in
+		circles. */
+		if fi, err := os.Stat(name); *recurse && nil == err &&
+			fi.IsDir() {
+			filepath.WalkDir(name, func(
+				path string,
+				d fs.DirEntry,
+				err error,
+			) error {
+				if done {
+					return filepath.SkipAll
+				}
+				/* Skip anything we can't read */
+				if nil != err {
+					fmt.Fprintf(os.Stderr, "Unable to "+
+						"read %v: %v\n", path, err)
+					ret = -1
+					if nil != d && d.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				/* Only scan regular files */
+				if !d.Type().IsRegular() {
+					return nil
+				}
+				if r := visit(path); 0 != r {
+					ret = r
+				}
+				return nil
+			})
+			continue
+		}
+		if r := visit(name); 0 != r {
+			ret = r
+		}
+	}
+	/* Scan the queued files in parallel.  Each file's output is kept
+	until it and every file before it have been scanned, so it comes
+	out in the same order as without -j. */
+	type result struct {
+		out  bytes.Buffer  /* What would have been printed */
+		ret  int           /* What scanFile returned */
+		done chan struct{} /* Closed when the file's been scanned */
+	}
+	results := make([]*result, len(queue))
+	for i := range results {
+		results[i] = &result{done: make(chan struct{})}
+	}
+	next := make(chan int) /* Index of the next file to scan */
+	go func() {
+		for i := range queue {
+			next <- i
+		}
+		close(next)
+	}()
+	for i := 0; i < *jobs && 0 != len(queue); i++ {
+		go func() {
+			for i := range next {
+				outMu.Lock()
+				skip := done
+				outMu.Unlock()
+				if !skip {
+					results[i].ret = scanFile(
+						queue[i],
+						&results[i].out,
+					)
+				}
+				close(results[i].done)
 			}
+		}()
+	}
+	var werr error /* Error printing a file's output */
+	for _, r := range results {
+		<-r.done
+		if 0 != r.ret {
+			ret = r.ret
+		}
+		if nil != werr {
 			continue
 		}
-		/* Update the digit buffer with the new digit */
-		digits = append(digits, buf...)
-		for len(digits) > *numlen { /* Should only loop once */
-			digits = digits[1:]
+		outMu.Lock()
+		_, werr = p.w.Write(r.out.Bytes())
+		outMu.Unlock()
+		if nil != werr {
+			fmt.Fprintf(os.Stderr, "Write error: %v\n", werr)
+			ret = -6
 		}
-		/* If we have enough, report it if it's a valid checksum */
-		if (len(digits) == *numlen) &&
-			((*mod10 && mod10Valid(digits)) ||
-				(!*mod10 && luhn.Valid(string(digits)))) {
-			fmt.Printf("%6v  %4v  %v\n",
-				nread-len(digits)-1,
-				nline,
-				string(digits))
+	}
 
+	/* Say how many numbers in the list were valid */
+	if *list && !*quiet && !*silent {
+		fmt.Fprintf(out, "%v of %v valid\n", total, nlist)
+	}
+
+	/* Write out anything buffered */
+	outMu.Lock()
+	defer outMu.Unlock()
+	if err := cout.Flush(); nil != err {
+		fmt.Fprintf(os.Stderr, "Write error: %v\n", err)
+		return -6
+	}
+	if "" != *outName {
+		if err := ofile.Close(); nil != err {
+			fmt.Fprintf(os.Stderr, "Write error: %v\n", err)
+			return -6
 		}
 	}
-	/* Should never get here */
-	fmt.Fprintf(os.Stderr, "Unpossible code execution.  Please debug.\n")
-	return -5
+	if nil != st {
+		st.print(os.Stderr, atomic.LoadInt64(&nbytes))
+	}
+	if timedOut {
+		fmt.Fprintf(os.Stderr, "Timed out after scanning %v bytes "+
+			"and finding %v matches.\n", atomic.LoadInt64(&nbytes),
+			total)
+		return -8
+	}
+	/* Like grep, 1 means nothing was found */
+	if 0 == ret && !found {
+		return 1
+	}
+	return ret
 }
 
-/* mod10Valid tests whether the input byte array is valid, according to the
-help output for -mod10 */
-func mod10Valid(digits []byte) bool {
-	exp := 0 /* Expected checksum */
-	/* Calculate the expected checksum */
-	for _, d := range digits[:len(digits)-1] {
-		exp = (exp + (int(d) - '0')) % 10
+/* lengths is a flag.Value which collects the argument to each use of a
+flag */
+type lengths []int
+
+/* String returns the lengths, separated by commas */
+func (l *lengths) String() string {
+	s := []string{}
+	for _, n := range *l {
+		s = append(s, strconv.Itoa(n))
+	}
+	return strings.Join(s, ",")
+}
+
+/* Set adds a length */
+func (l *lengths) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if nil != err {
+		return err
+	}
+	*l = append(*l, n)
+	return nil
+}
+
+/* errStop is returned while reporting a match to stop scanning early */
+var errStop = errors.New("stop scanning")
+
+/* weights is a flag.Value which holds a comma-separated list of weights */
+type weights []int
+
+/* String returns the weights, separated by commas */
+func (w *weights) String() string {
+	return (*lengths)(w).String()
+}
+
+/* Set replaces the weights with those in s */
+func (w *weights) Set(s string) error {
+	ws := []int{}
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if nil != err {
+			return err
+		}
+		ws = append(ws, n)
+	}
+	*w = ws
+	return nil
+}
+
+/* byteSize is a flag.Value which holds a number of bytes, as parsed by
+parseSize */
+type byteSize int64
+
+/* String returns the number of bytes */
+func (b *byteSize) String() string {
+	return strconv.FormatInt(int64(*b), 10)
+}
+
+/* Set sets the number of bytes from s */
+func (b *byteSize) Set(s string) error {
+	n, err := parseSize(s)
+	if nil != err {
+		return err
+	}
+	*b = byteSize(n)
+	return nil
+}
+
+/* parseSize parses a number of bytes, which may end in K, M, G or T (or k, m,
+g or t) for KB, MB, GB or TB, counted in powers of 1024, so 10M is 10485760.
+An optional B may follow, as in 10MB. */
+func parseSize(s string) (int64, error) {
+	t := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(s)), "B")
+	mult := int64(1)
+	if "" != t {
+		if i := strings.IndexByte("KMGT", t[len(t)-1]); -1 != i {
+			mult <<= 10 * uint(i+1)
+			t = t[:len(t)-1]
+		}
+	}
+	n, err := strconv.ParseInt(t, 10, 64)
+	if nil != err {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	if 0 > n || n > math.MaxInt64/mult {
+		return 0, fmt.Errorf("size %q out of range", s)
 	}
-	/* Print the match if we have it */
-	return int(digits[len(digits)-1]-'0') == exp
+	return n * mult, nil
 }
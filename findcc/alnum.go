@@ -0,0 +1,94 @@
+/*
+ * alnum.go
+ * Luhn mod N, for numbers made of letters as well as digits
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package findcc
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/* DefaultAlphabet is the alphabet LuhnModN uses if Scanner.Alphabet isn't
+set: base 36, with A worth 10 and Z worth 35 */
+const DefaultAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+/* luhnModN returns true if s has a valid check character according to the
+Luhn mod N algorithm, where N is the length of alphabet and each character is
+worth its position in alphabet.  From the check character leftwards, every
+other character's value is doubled, and the two base-N digits of a doubled
+value are added together, just as with Luhn's algorithm and base 10.  The sum
+of them all must be a multiple of N.  A character not in alphabet makes s
+invalid. */
+func luhnModN(s string, alphabet string) bool {
+	if "" == s {
+		return false
+	}
+	n := len(alphabet)
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(alphabet, s[len(s)-1-i])
+		if -1 == v {
+			return false
+		}
+		if 1 == i%2 {
+			v *= 2
+			v = v/n + v%n
+		}
+		sum += v
+	}
+	return 0 == sum%n
+}
+
+/* alnumValue returns the character in alphabet which c stands for.  Unicode
+decimal digits stand for ASCII digits, as they do elsewhere, and letters not
+in alphabet stand for their uppercase versions if those are.  If c doesn't
+stand for anything in alphabet, ok is false. */
+func alnumValue(c rune, alphabet string) (d byte, ok bool) {
+	if v, ok := digitValue(c); ok {
+		c = rune(v)
+	}
+	if !strings.ContainsRune(alphabet, c) {
+		c = unicode.ToUpper(c)
+	}
+	if utf8.RuneSelf <= c || !strings.ContainsRune(alphabet, c) {
+		return 0, false
+	}
+	return byte(c), true
+}
+
+/* checkAlphabet returns an error if alphabet can't be used with LuhnModN.  It
+needs at least two characters, each printable ASCII and none repeated. */
+func checkAlphabet(alphabet string) error {
+	if 2 > len(alphabet) {
+		return fmt.Errorf("alphabet %q is too short", alphabet)
+	}
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if ' ' >= c || '~' < c {
+			return fmt.Errorf("invalid alphabet character %q", c)
+		}
+		if i != strings.IndexByte(alphabet, c) {
+			return fmt.Errorf("alphabet character %q repeated", c)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+ * brand.go
+ * Identify the brand of a card number
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package findcc
+
+import "strconv"
+
+/* brands maps ranges of IIN prefixes to card brands, for the lengths the
+brand issues.  None of the ranges overlap. */
+var brands = []struct {
+	name      string /* Brand name */
+	low, high int    /* Range of prefixes, with the same number of digits */
+	lens      []int  /* Valid lengths */
+}{
+	{"Amex", 34, 34, []int{15}},
+	{"Amex", 37, 37, []int{15}},
+	{"Diners", 300, 305, []int{14, 15, 16, 17, 18, 19}},
+	{"Diners", 36, 36, []int{14, 15, 16, 17, 18, 19}},
+	{"Diners", 38, 39, []int{14, 15, 16, 17, 18, 19}},
+	{"Discover", 6011, 6011, []int{16, 17, 18, 19}},
+	{"Discover", 644, 649, []int{16, 17, 18, 19}},
+	{"Discover", 65, 65, []int{16, 17, 18, 19}},
+	{"JCB", 3528, 3589, []int{16, 17, 18, 19}},
+	{"Mastercard", 2221, 2720, []int{16}},
+	{"Mastercard", 51, 55, []int{16}},
+	{"UnionPay", 62, 62, []int{16, 17, 18, 19}},
+	{"Visa", 4, 4, []int{13, 16, 19}},
+}
+
+/* Brand returns the brand of the card number n, worked out from its prefix
+and length, or "unknown" if it doesn't look like any known brand's. */
+func Brand(n string) string {
+	for _, b := range brands {
+		/* Get the prefix to check */
+		l := len(strconv.Itoa(b.low))
+		if len(n) < l {
+			continue
+		}
+		p, err := strconv.Atoi(n[:l])
+		if nil != err || p < b.low || p > b.high {
+			continue
+		}
+		/* Make sure it's the right length */
+		for _, bl := range b.lens {
+			if len(n) == bl {
+				return b.name
+			}
+		}
+	}
+	return "unknown"
+}
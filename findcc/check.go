@@ -0,0 +1,123 @@
+/*
+ * check.go
+ * Check a single number
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package findcc
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+/* Valid returns true if n has a valid check digit according to s.Algorithm, or
+any of s.Algorithms if it's set.  The same characters are allowed as in a
+number found by Scan, so Unicode decimal digits are fine, as are an ISBN-10's
+final X, an IBAN's letters and the characters in a LuhnModN alphabet.  Anything
+else, including separators, causes an error. */
+func (s *Scanner) Valid(n string) (bool, error) {
+	digits, err := s.digits(n)
+	if nil != err {
+		return false, err
+	}
+	algs, err := s.algorithms()
+	if nil != err {
+		return false, err
+	}
+	var lw luhnWindow
+	for _, d := range digits {
+		lw.push(d)
+	}
+	for _, a := range algs {
+		if s.valid(a, digits, &lw) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+/* CheckDigit returns the check digit which makes body valid according to
+s.Algorithm when added to its end, or its start with s.LeadingCheck.  It's
+found by trying each possible digit with Valid, so the two always agree.  Not
+every weighted sum has a check digit for every body, and an IBAN's check digits
+aren't at the end, so either causes an error, as does setting s.Algorithms to
+more than one algorithm. */
+func (s *Scanner) CheckDigit(body string) (byte, error) {
+	if 1 < len(s.Algorithms) {
+		return 0, fmt.Errorf("more than one algorithm")
+	}
+	if s.uses(IBAN) {
+		return 0, fmt.Errorf("IBAN check digits aren't at the end")
+	}
+	cs := "0123456789"
+	if s.uses(ISBN10) {
+		cs += "X"
+	}
+	if s.uses(LuhnModN) {
+		cs = s.alphabet()
+	}
+	for i := 0; i < len(cs); i++ {
+		n := body + cs[i:i+1]
+		if s.LeadingCheck {
+			n = cs[i:i+1] + body
+		}
+		ok, err := s.Valid(n)
+		if nil != err {
+			return 0, err
+		}
+		if ok {
+			return cs[i], nil
+		}
+	}
+	return 0, fmt.Errorf("no check digit is valid")
+}
+
+/* digits converts n to the ASCII digits, Xs and letters Scan would report */
+func (s *Scanner) digits(n string) ([]byte, error) {
+	if "" == n {
+		return nil, fmt.Errorf("no digits")
+	}
+	digits := make([]byte, 0, len(n))
+	for i, c := range n {
+		d, ok := digitValue(c)
+		if !ok && s.uses(ISBN10) && ('X' == c || 'x' == c) &&
+			s.checkPos(n, i, c) {
+			d, ok = 'X', true
+		}
+		if !ok && s.uses(IBAN) && isIBANLetter(c) {
+			d, ok = byte(unicode.ToUpper(c)), true
+		}
+		if s.uses(LuhnModN) {
+			d, ok = alnumValue(c, s.alphabet())
+		}
+		if !ok {
+			return nil, fmt.Errorf("invalid character %q", c)
+		}
+		digits = append(digits, d)
+	}
+	return digits, nil
+}
+
+/* checkPos returns true if c, at byte i of n, is where the check digit goes */
+func (s *Scanner) checkPos(n string, i int, c rune) bool {
+	if s.LeadingCheck {
+		return 0 == i
+	}
+	return len(n) == i+utf8.RuneLen(c)
+}
@@ -0,0 +1,212 @@
+/*
+ * checksum.go
+ * Check digit algorithms
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package findcc
+
+/* Algorithm is a check digit algorithm */
+type Algorithm int
+
+/* Check digit algorithms a Scanner can use */
+const (
+	Luhn     Algorithm = iota /* Luhn, as used by credit cards */
+	Mod10                     /* Simple sum modulus 10 */
+	Verhoeff                  /* Verhoeff's dihedral group algorithm */
+	Damm                      /* Damm's quasigroup algorithm */
+	ISBN10                    /* ISBN-10's weighted sum modulus 11 */
+	IBAN                      /* IBAN's modulus 97, with letters */
+	ModN                      /* Weighted sum modulus Scanner.Mod */
+	LuhnModN                  /* Luhn over Scanner.Alphabet */
+)
+
+/* String returns the name of the algorithm, in lowercase */
+func (a Algorithm) String() string {
+	switch a {
+	case Luhn:
+		return "luhn"
+	case Mod10:
+		return "mod10"
+	case Verhoeff:
+		return "verhoeff"
+	case Damm:
+		return "damm"
+	case ISBN10:
+		return "isbn10"
+	case IBAN:
+		return "iban"
+	case ModN:
+		return "modn"
+	case LuhnModN:
+		return "luhnmodn"
+	}
+	return "unknown"
+}
+
+/* mod10Valid tests whether the input byte array is valid, according to the
+help output for -mod10 */
+func mod10Valid(digits []byte) bool {
+	exp := 0 /* Expected checksum */
+	/* Calculate the expected checksum */
+	for _, d := range digits[:len(digits)-1] {
+		exp = (exp + (int(d) - '0')) % 10
+	}
+	/* Print the match if we have it */
+	return int(digits[len(digits)-1]-'0') == exp
+}
+
+/* luhnWindow keeps the Luhn sum of a window of digits which slides along the
+input a digit at a time.  Adding a digit on the right moves every other digit
+one place to the left, which swaps which ones are doubled, so the sum is kept
+both ways. */
+type luhnWindow struct {
+	sum  int /* Sum with the rightmost digit not doubled */
+	flip int /* Sum with the rightmost digit doubled */
+}
+
+/* luhnDouble returns the Luhn value of the doubled digit d */
+func luhnDouble(d int) int {
+	if d *= 2; 9 < d {
+		d -= 9
+	}
+	return d
+}
+
+/* push adds the digit c on the right of the window */
+func (w *luhnWindow) push(c byte) {
+	d := int(c - '0')
+	w.sum, w.flip = w.flip+d, w.sum+luhnDouble(d)
+}
+
+/* pop removes the digit c from the window, where c is pos places from the
+right, counting the rightmost as 0 */
+func (w *luhnWindow) pop(c byte, pos int) {
+	d := int(c - '0')
+	if 0 == pos%2 {
+		w.sum -= d
+		w.flip -= luhnDouble(d)
+	} else {
+		w.sum -= luhnDouble(d)
+		w.flip -= d
+	}
+}
+
+/* valid returns true if the digits in the window pass the Luhn check */
+func (w *luhnWindow) valid() bool {
+	return 0 == w.sum%10
+}
+
+/* verhoeffD is the multiplication table of the dihedral group D5 */
+var verhoeffD = [10][10]byte{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+	{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+	{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+	{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+	{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+	{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+	{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+	{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+	{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+}
+
+/* verhoeffP is the permutation applied to a digit, by its position from the
+right modulo 8 */
+var verhoeffP = [8][10]byte{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+	{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+	{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+	{9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+	{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+	{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+	{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+}
+
+/* verhoeffInv is the inverse of each element of D5, used to generate check
+digits */
+var verhoeffInv = [10]byte{0, 4, 3, 2, 1, 5, 6, 7, 8, 9}
+
+/* verhoeffValid tests whether the input byte array, including its check
+digit, is valid according to the Verhoeff algorithm */
+func verhoeffValid(digits []byte) bool {
+	c := byte(0)
+	for i := range digits {
+		d := digits[len(digits)-1-i] - '0'
+		c = verhoeffD[c][verhoeffP[i%8][d]]
+	}
+	return 0 == c
+}
+
+/* dammTable is the totally anti-symmetric quasigroup of order 10 used by the
+Damm algorithm */
+var dammTable = [10][10]byte{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+/* dammValid tests whether the input byte array, including its check digit,
+is valid according to the Damm algorithm */
+func dammValid(digits []byte) bool {
+	c := byte(0)
+	for _, d := range digits {
+		c = dammTable[c][d-'0']
+	}
+	return 0 == c
+}
+
+/* isbn10Valid tests whether the input byte array is valid according to
+ISBN-10's check.  Each digit is weighted by its position from the right,
+counting the check digit as 1, and the sum must be a multiple of 11.  The
+check digit may be an X, standing for 10. */
+func isbn10Valid(digits []byte) bool {
+	sum := 0
+	for i := range digits {
+		c := digits[len(digits)-1-i]
+		d := int(c - '0')
+		if 'X' == c {
+			d = 10
+		}
+		sum += (i + 1) * d
+	}
+	return 0 == sum%11
+}
+
+/* modNValid tests whether the input byte array is valid according to a
+weighted sum modulus mod.  The weights are used in turn, starting again when
+they run out, from the check digit leftwards, and the weighted sum of all of
+the digits must be a multiple of mod.  No weights means every weight is 1. */
+func modNValid(digits []byte, mod int, weights []int) bool {
+	sum := 0
+	for i := range digits {
+		w := 1
+		if 0 != len(weights) {
+			w = weights[i%len(weights)]
+		}
+		sum = (sum + w*int(digits[len(digits)-1-i]-'0')) % mod
+	}
+	return 0 == sum
+}
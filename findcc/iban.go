@@ -0,0 +1,91 @@
+/*
+ * iban.go
+ * Validate International Bank Account Numbers
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package findcc
+
+import "sort"
+
+/* ibanLengths is the length of an IBAN in each country which uses them, from
+the SWIFT IBAN registry */
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16,
+	"BG": 22, "BH": 22, "BI": 27, "BR": 29, "BY": 28, "CH": 21, "CR": 22,
+	"CY": 28, "CZ": 24, "DE": 22, "DJ": 27, "DK": 18, "DO": 28, "EE": 20,
+	"EG": 29, "ES": 24, "FI": 18, "FK": 18, "FO": 18, "FR": 27, "GB": 22,
+	"GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28,
+	"IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30,
+	"KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21,
+	"LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20, "MR": 27,
+	"MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23, "PK": 24,
+	"PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "RU": 33,
+	"SA": 24, "SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27,
+	"SO": 23, "ST": 25, "SV": 28, "TL": 23, "TN": 24, "TR": 26, "UA": 29,
+	"VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+/* ibanLens returns every length an IBAN may have, shortest first */
+func ibanLens() []int {
+	lens := []int{}
+	seen := map[int]bool{}
+	for _, l := range ibanLengths {
+		if !seen[l] {
+			lens = append(lens, l)
+			seen[l] = true
+		}
+	}
+	sort.Ints(lens)
+	return lens
+}
+
+/* isIBANLetter returns true if c is a letter which may appear in an IBAN */
+func isIBANLetter(c rune) bool {
+	return ('A' <= c && 'Z' >= c) || ('a' <= c && 'z' >= c)
+}
+
+/* ibanValid tests whether the input byte array, which may contain digits and
+uppercase letters, is an IBAN.  It must start with a country code and two
+check digits and be the right length for the country.  Moving the first four
+characters to the end and replacing each letter with its two-digit value, A
+being 10, must give a number which is 1 modulus 97.  The remainder is worked
+out a digit or letter at a time, so the number never gets too big. */
+func ibanValid(digits []byte) bool {
+	/* Country code and check digits */
+	if 4 > len(digits) ||
+		'A' > digits[0] || 'Z' < digits[0] ||
+		'A' > digits[1] || 'Z' < digits[1] ||
+		'0' > digits[2] || '9' < digits[2] ||
+		'0' > digits[3] || '9' < digits[3] {
+		return false
+	}
+	if ibanLengths[string(digits[:2])] != len(digits) {
+		return false
+	}
+	/* Remainder of the rearranged number */
+	r := 0
+	for i := range digits {
+		c := digits[(i+4)%len(digits)]
+		if 'A' <= c && 'Z' >= c {
+			r = (r*100 + int(c-'A') + 10) % 97
+		} else {
+			r = (r*10 + int(c-'0')) % 97
+		}
+	}
+	return 1 == r
+}
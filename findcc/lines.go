@@ -0,0 +1,50 @@
+/*
+ * lines.go
+ * Work out where lines end
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package findcc
+
+/* What a character is to the line it's on */
+const (
+	notLineEnd = iota /* Not the end of a line */
+	lineEnd           /* The end of a line */
+	crlfEnd           /* The \n of a \r\n, which was the end of a line */
+)
+
+/* lineEnds tells where lines end, for counting lines.  A line ends with a \n,
+a \r, or a \r\n, which is only one line break.  The zero value is ready to
+use, at the start of a line. */
+type lineEnds struct {
+	afterCR bool /* Last character was a \r */
+}
+
+/* kind returns notLineEnd, lineEnd or crlfEnd for c, the next character */
+func (l *lineEnds) kind(c rune) int {
+	afterCR := l.afterCR
+	l.afterCR = '\r' == c
+	switch {
+	case '\r' == c:
+		return lineEnd
+	case '\n' == c && afterCR:
+		return crlfEnd
+	case '\n' == c:
+		return lineEnd
+	}
+	return notLineEnd
+}
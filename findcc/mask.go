@@ -0,0 +1,56 @@
+/*
+ * mask.go
+ * Hide the middle digits of a number
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package findcc
+
+import "unicode"
+
+/* Mask replaces all but the first six and last four digits of n with *s, so
+that 4111111111111111 becomes 411111******1111.  Numbers of ten or fewer
+digits, which would otherwise not be masked at all, have every digit but the
+last replaced.  Anything in n which isn't a digit, such as a separator, is left
+alone. */
+func Mask(n string) string {
+	/* Count the digits */
+	nd := 0
+	for _, c := range n {
+		if unicode.IsDigit(c) {
+			nd++
+		}
+	}
+	/* Work out which to hide */
+	first, last := 6, nd-4
+	if 10 >= nd {
+		first, last = 0, nd-1
+	}
+	/* Hide them */
+	m := []rune{}
+	i := 0
+	for _, c := range n {
+		if unicode.IsDigit(c) {
+			if i >= first && i < last {
+				c = '*'
+			}
+			i++
+		}
+		m = append(m, c)
+	}
+	return string(m)
+}
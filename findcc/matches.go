@@ -0,0 +1,69 @@
+/*
+ * matches.go
+ * Receive matches on a channel
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package findcc
+
+import (
+	"context"
+	"io"
+)
+
+/* Matches scans r in a new goroutine and sends each match found on the
+returned channel, which is closed when scanning stops.  Scanning stops at the
+end of r, on a read error, or when ctx is done.  Afterwards, Err returns why,
+if it wasn't the end of r.  The goroutine only exits once scanning stops, so
+callers who stop receiving before the channel is closed should cancel ctx;
+it's checked whenever a match is found and every few KB of input.  A read
+which blocks forever keeps the goroutine forever.  Only one call to Matches
+per Scanner should be in progress at once, as there's only one Err. */
+func (s *Scanner) Matches(ctx context.Context, r io.Reader) <-chan Match {
+	ch := make(chan Match)
+	s.errMu.Lock()
+	s.err = nil
+	s.errMu.Unlock()
+	go func() {
+		defer close(ch)
+		err := s.ScanContext(ctx, r, func(m Match) error {
+			/* Don't send anything once we're done */
+			if err := ctx.Err(); nil != err {
+				return err
+			}
+			select {
+			case ch <- m:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		s.errMu.Lock()
+		s.err = err
+		s.errMu.Unlock()
+	}()
+	return ch
+}
+
+/* Err returns the error which stopped the last call to Matches, or nil if it
+reached the end of its input.  It's only meaningful once the channel from
+Matches has been closed. */
+func (s *Scanner) Err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.err
+}
@@ -0,0 +1,157 @@
+/*
+ * parallel.go
+ * Scan pieces of a file at the same time
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package findcc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+)
+
+/* piece is part of the input being scanned by ScanParallel */
+type piece struct {
+	start   int64   /* Offset of the first byte */
+	end     int64   /* Offset just after the last byte */
+	matches []Match /* Matches found, relative to start */
+	nline   int     /* Newlines in the piece */
+	err     error   /* Error scanning the piece */
+}
+
+/* ScanParallel is like Scan, but splits the size bytes of r into up to n
+pieces and scans them at the same time.  Pieces start just after newlines.
+Numbers can't contain newlines, so none is split between two pieces, and line
+numbers and columns carry on from the previous piece.  Matches are still passed
+to fn in order, from the calling goroutine, but only once the piece they're in
+has been scanned.  If Context is set or Seps contains a newline, r is scanned
+in one piece.  As with ScanContext, scanning stops once ctx is done. */
+func (s *Scanner) ScanParallel(
+	ctx context.Context,
+	r io.ReaderAt,
+	size int64,
+	n int,
+	fn func(Match) error,
+) error {
+	if 0 != s.Context || strings.ContainsRune(s.Seps, '\n') {
+		n = 1
+	}
+	/* Work out where the pieces start and end */
+	ps := []*piece{}
+	start := int64(0)
+	for i := 1; i <= n && start < size; i++ {
+		end := size
+		if i < n {
+			var err error
+			end, err = nextLine(r, size, size/int64(n)*int64(i))
+			if nil != err {
+				return err
+			}
+		}
+		if end <= start {
+			continue
+		}
+		ps = append(ps, &piece{start: start, end: end})
+		start = end
+	}
+	/* Scan them all, until we're done with them */
+	var wg sync.WaitGroup
+	ctx, cancel := context.WithCancel(ctx)
+	done := make([]chan struct{}, len(ps))
+	for i, p := range ps {
+		done[i] = make(chan struct{})
+		wg.Add(1)
+		go func(p *piece, done chan<- struct{}) {
+			defer wg.Done()
+			defer close(done)
+			lr := &lineCounter{r: io.NewSectionReader(
+				r,
+				p.start,
+				p.end-p.start,
+			)}
+			p.err = s.ScanContext(ctx, lr, func(m Match) error {
+				if err := ctx.Err(); nil != err {
+					return err
+				}
+				p.matches = append(p.matches, m)
+				return nil
+			})
+			p.nline = lr.n
+		}(p, done[i])
+	}
+	/* Report the matches in order */
+	defer wg.Wait()
+	defer cancel()
+	nline := 0 /* Newlines before the piece */
+	for i, p := range ps {
+		<-done[i]
+		for _, m := range p.matches {
+			m.Offset += int(p.start)
+			m.Line += nline
+			if err := fn(m); nil != err {
+				return err
+			}
+		}
+		if nil != p.err {
+			return p.err
+		}
+		nline += p.nline
+	}
+	return nil
+}
+
+/* nextLine returns the offset just after the first newline in r at or after
+off, or size if there isn't one */
+func nextLine(r io.ReaderAt, size, off int64) (int64, error) {
+	buf := make([]byte, 64*1024)
+	for off < size {
+		n, err := r.ReadAt(buf, off)
+		if i := bytes.IndexByte(buf[:n], '\n'); -1 != i {
+			return off + int64(i) + 1, nil
+		}
+		off += int64(n)
+		if io.EOF == err {
+			break
+		}
+		if nil != err {
+			return 0, err
+		}
+	}
+	return size, nil
+}
+
+/* lineCounter counts the lines ended in what's read from r */
+type lineCounter struct {
+	r    io.Reader
+	n    int
+	ends lineEnds
+}
+
+/* Read reads from r and counts the ends of lines */
+func (l *lineCounter) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	for _, c := range p[:n] {
+		if lineEnd == l.ends.kind(rune(c)) {
+			l.n++
+		}
+	}
+	return n, err
+}
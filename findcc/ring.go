@@ -0,0 +1,65 @@
+/*
+ * ring.go
+ * Fixed-size ring buffer
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package findcc
+
+/* ring is a fixed-size buffer of the most recent bytes pushed into it.  Each
+byte is stored twice, size bytes apart, so the contents are always available
+in order as a single slice without copying. */
+type ring struct {
+	buf  []byte /* Storage, twice the size of the ring */
+	size int    /* Maximum number of bytes held */
+	head int    /* Index of the oldest byte */
+	n    int    /* Number of bytes held */
+}
+
+/* newRing returns a ring which holds up to size bytes */
+func newRing(size int) *ring {
+	return &ring{buf: make([]byte, 2*size), size: size}
+}
+
+/* push adds b to the ring, dropping the oldest byte if the ring is full */
+func (r *ring) push(b byte) {
+	if r.n == r.size {
+		r.drop()
+	}
+	i := (r.head + r.n) % r.size
+	r.buf[i] = b
+	r.buf[i+r.size] = b
+	r.n++
+}
+
+/* drop removes the oldest byte from the ring */
+func (r *ring) drop() {
+	r.head = (r.head + 1) % r.size
+	r.n--
+}
+
+/* reset empties the ring */
+func (r *ring) reset() {
+	r.head = 0
+	r.n = 0
+}
+
+/* bytes returns the contents of the ring, oldest first.  The returned slice
+is only valid until the next push. */
+func (r *ring) bytes() []byte {
+	return r.buf[r.head : r.head+r.n]
+}
@@ -0,0 +1,531 @@
+/*
+ * scanner.go
+ * Find n-digit numbers with a valid check digit in a stream of bytes
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+/* Package findcc finds sequences of digits with a valid check digit, such as
+credit card numbers, in arbitrary input. */
+package findcc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+/* Match is a number found by a Scanner */
+type Match struct {
+	Offset    int       /* Offset of the first digit, counting from 0 */
+	Line      int       /* Number of line breaks before the number */
+	Column    int       /* Characters into the line, counting from 1 */
+	Number    string    /* The number in ASCII, with its check digit */
+	Raw       string    /* The number as it appeared, with separators */
+	Before    string    /* Up to Scanner.Context bytes before the number */
+	After     string    /* Up to Scanner.Context bytes after the number */
+	Algorithm Algorithm /* Algorithm the number is valid with */
+}
+
+/* Scanner searches input for sequences of Len digits with a valid check digit,
+according to Algorithm.  If Lens is set, numbers of each of its lengths are
+searched for instead.  A single byte from Seps may appear between any two
+digits of a number without breaking it up, so "4111 1111 1111 1111" may be
+found with a Seps of " ".  With ISBN10, the last digit may also be an X or x,
+reported as X.  With IBAN, ASCII letters are treated as digits and reported in
+uppercase, and if neither Len nor Lens is set, IBANs of every country's length
+are found.  With LuhnModN, the characters in Alphabet, DefaultAlphabet if it's
+not set, are treated as digits, each worth its position in Alphabet, and
+letters not in it are looked for in uppercase.  If Algorithms is set, its
+algorithms are all used instead, and a number valid with more than one of them
+is reported once for each, though IBAN and LuhnModN can't be used with any
+other.  With ModN, Mod and Weights describe the check.  If Context is set,
+matches are held back until that many bytes after them have been read, or the
+input ends, so the bytes around them can be reported. */
+type Scanner struct {
+	Len        int         /* Length of number, including check digit */
+	Lens       []int       /* Lengths of numbers to find, if not just Len */
+	Algorithm  Algorithm   /* Check digit algorithm, Luhn by default */
+	Algorithms []Algorithm /* Algorithms to use, if not just Algorithm */
+	Seps       string      /* Separators allowed between digits */
+	SkipTest   bool        /* Don't report numbers in TestNumbers */
+	Mod        int         /* Modulus, with ModN */
+	Weights    []int       /* Weights, from the right, with ModN */
+	Context    int         /* Bytes of context to report around matches */
+	Alphabet   string      /* Characters, in order, with LuhnModN */
+
+	/* If NoOverlap is set, the digits of a match aren't used again, so a
+	long run of digits doesn't produce a match at nearly every digit.
+	This misses numbers which start inside a previous match. */
+	NoOverlap bool
+
+	/* If SkipTrivial is set, numbers too regular to be real, such as
+	0000000000000000 or 1234567890123452, aren't reported, even though
+	they're valid.  See looksTrivial for what counts. */
+	SkipTrivial bool
+
+	/* If LeadingCheck is set, the check digit is the first digit rather
+	than the last, and the other digits' weights count rightwards from
+	it, so a number is valid if it's valid backwards.  With ISBN10, an X
+	may then start a number, but not end it.  IBAN can't be used with
+	LeadingCheck. */
+	LeadingCheck bool
+
+	errMu sync.Mutex /* Protects err */
+	err   error      /* Error which stopped Matches */
+}
+
+/* ctxCheckBytes is how many bytes ScanContext reads between checks for
+cancellation */
+const ctxCheckBytes = 4096
+
+/* pendingMatch is a match waiting for the context after it */
+type pendingMatch struct {
+	m   Match /* The match */
+	end int   /* Offset just after the match */
+}
+
+/* position is where a digit was found */
+type position struct {
+	offset int /* Offset of the digit's first byte */
+	line   int /* Number of line breaks before the digit */
+	column int /* Characters into the line */
+}
+
+/* Scan reads r until EOF, calling fn with each match found.  If more than one
+length of number is being searched for, numbers ending on the same digit are
+reported shortest first, and a number valid with more than one algorithm is
+reported for each in the order they're in Algorithms.  Lines end with a \n, a
+\r, or a \r\n, which is only one line break.  If fn returns an error, scanning
+stops and the error is returned.  Read errors other than io.EOF are also
+returned.  A reader which returns neither data nor an error causes
+io.ErrNoProgress to be returned.  If r is an io.RuneScanner and an
+io.ByteReader, such as a bytes.Reader, runes are read from it directly instead
+of through a bufio.Reader. */
+func (s *Scanner) Scan(r io.Reader, fn func(Match) error) error {
+	return s.ScanContext(context.Background(), r, fn)
+}
+
+/* ScanContext is like Scan, but stops with ctx's error once ctx is done.
+Cancellation is checked every few KB of input, so scanning stops promptly even
+on huge inputs, though a Read which blocks isn't interrupted.  Matches already
+passed to fn are unaffected, but matches held back for Context are dropped. */
+func (s *Scanner) ScanContext(
+	ctx context.Context,
+	r io.Reader,
+	fn func(Match) error,
+) error {
+	/* Work out how long the numbers can be */
+	lens, err := s.lengths()
+	if nil != err {
+		return err
+	}
+	max := lens[len(lens)-1]
+	algs, err := s.algorithms()
+	if nil != err {
+		return err
+	}
+	alphabet := s.alphabet()
+	/* Buffer of sequential input digits, as ASCII */
+	digits := newRing(max)
+	/* The digits and separators as they appeared in the input, with room
+	for a separator after each digit */
+	raw := newRing(2 * max * utf8.UTFMax)
+	/* Where each digit in the buffer started, by digit number modulo
+	max */
+	starts := make([]position, max)
+	ndigits := 0                         /* Number of digits in this run */
+	lws := make([]luhnWindow, len(lens)) /* Luhn sums for each length */
+	br := runeReader(r)                  /* Read buffer */
+	nline := 0                           /* Number of lines ended */
+	var ends lineEnds                    /* Finds the ends of lines */
+	ncol := 0                            /* Characters read on this line */
+	nread := 0                           /* Number of bytes read */
+	nextCheck := 0                       /* When to next check ctx */
+	/* Recent input, for context.  Enough is kept for the bytes before
+	a match, the match itself, and the bytes after the oldest match
+	waiting for them. */
+	hist := newRing(2*s.Context + 2*max*utf8.UTFMax + utf8.UTFMax)
+	var pending []pendingMatch /* Matches waiting for context */
+	/* flush reports the pending matches which have enough context
+	after them, or all of them if all is true */
+	flush := func(all bool) error {
+		for 0 != len(pending) {
+			pm := pending[0]
+			since := nread - pm.end /* Bytes read after match */
+			if !all && since < s.Context {
+				return nil
+			}
+			if since > s.Context {
+				since = s.Context
+			}
+			start := hist.n - (nread - pm.end)
+			pm.m.After = string(hist.bytes()[start : start+since])
+			pending = pending[1:]
+			if err := fn(pm.m); nil != err {
+				return err
+			}
+		}
+		return nil
+	}
+	/* report reports m, or holds it back for context */
+	report := func(m Match) error {
+		if 0 == s.Context {
+			return fn(m)
+		}
+		/* Before the match is whatever we've still got of the
+		Context bytes before it */
+		end := hist.n - (nread - m.Offset)
+		start := end - s.Context
+		if 0 > start {
+			start = 0
+		}
+		m.Before = string(hist.bytes()[start:end])
+		pending = append(pending, pendingMatch{m: m, end: nread})
+		return nil
+	}
+	/* reset forgets the digits seen so far */
+	reset := func() {
+		digits.reset()
+		raw.reset()
+		ndigits = 0
+		for i := range lws {
+			lws[i] = luhnWindow{}
+		}
+	}
+	/* Read until EOF */
+	for {
+		/* Give up if we've been told to */
+		if nread >= nextCheck {
+			if err := ctx.Err(); nil != err {
+				return err
+			}
+			nextCheck = nread + ctxCheckBytes
+		}
+		/* Read a character.  bufio returns io.ErrNoProgress if the
+		reader keeps returning neither data nor an error.  Invalid
+		UTF-8 comes back as one-byte utf8.RuneErrors, which aren't
+		digits. */
+		c, size, err := br.ReadRune()
+		if nil != err {
+			/* Report what we've held back, with what context
+			there is */
+			if ferr := flush(true); nil != ferr {
+				return ferr
+			}
+			/* Don't whine if we've reached EOF */
+			if io.EOF == err {
+				return nil
+			}
+			return err
+		}
+		/* Note how many bytes we've read */
+		nread += size
+		/* Keep it for context and report anything which now has
+		enough */
+		if 0 != s.Context {
+			if utf8.RuneError == c && 1 == size {
+				/* Keep the invalid byte itself */
+				br.UnreadRune()
+				b, _ := br.ReadByte()
+				hist.push(b)
+			} else {
+				pushRune(hist, c)
+			}
+			if err := flush(false); nil != err {
+				return err
+			}
+		}
+		/* Note if it ends a line.  The \n of a \r\n isn't on
+		either line. */
+		switch ends.kind(c) {
+		case lineEnd:
+			nline++
+			ncol = 0
+		case notLineEnd:
+			ncol++
+		}
+		/* A separator is kept if it follows a digit */
+		if 0 < raw.n && isSep(c, s.Seps) {
+			last, _ := utf8.DecodeLastRune(raw.bytes())
+			if !isSep(last, s.Seps) {
+				pushRune(raw, c)
+				continue
+			}
+		}
+		/* If it's not a digit, clear any waiting digits, try again.
+		ISBN-10's check digit may also be an X, which ends the
+		number, or with LeadingCheck starts a new one. */
+		d, ok := digitValue(c)
+		last := false
+		if !ok && s.uses(ISBN10) && ('X' == c || 'x' == c) {
+			switch {
+			case s.LeadingCheck:
+				reset()
+				d, ok = 'X', true
+			case 0 < ndigits:
+				d, ok, last = 'X', true, true
+			}
+		}
+		/* IBANs also have letters, which are kept in uppercase */
+		if !ok && s.uses(IBAN) && isIBANLetter(c) {
+			d, ok = byte(unicode.ToUpper(c)), true
+		}
+		/* With LuhnModN, the alphabet says what's a digit */
+		if s.uses(LuhnModN) {
+			d, ok = alnumValue(c, alphabet)
+		}
+		if !ok {
+			if 0 < ndigits {
+				reset()
+			}
+			continue
+		}
+		/* Slide each length's Luhn sum along to the new digit */
+		for i, l := range lens {
+			if ndigits >= l {
+				lws[i].pop(digits.bytes()[digits.n-l], l-1)
+			}
+			lws[i].push(d)
+		}
+		/* Update the digit buffer with the new digit */
+		starts[ndigits%max] = position{nread - size, nline, ncol}
+		ndigits++
+		digits.push(d)
+		pushRune(raw, c)
+		/* Report any lengths we have enough for with a valid
+		checksum, once for each algorithm it's valid with */
+		for i, l := range lens {
+			if ndigits < l {
+				break
+			}
+			w := digits.bytes()[digits.n-l:]
+			found := false
+			for _, a := range algs {
+				if !s.valid(a, w, &lws[i]) {
+					continue
+				}
+				/* Skip published test numbers if asked */
+				if s.SkipTest && TestNumbers[string(w)] {
+					continue
+				}
+				/* Likewise numbers no real card would have */
+				if s.SkipTrivial && looksTrivial(w) {
+					continue
+				}
+				found = true
+				p := starts[(ndigits-l)%max]
+				rb := raw.bytes()[raw.n-(nread-p.offset):]
+				if err := report(Match{
+					Offset:    p.offset,
+					Line:      p.line,
+					Column:    p.column,
+					Number:    string(w),
+					Raw:       string(rb),
+					Algorithm: a,
+				}); nil != err {
+					return err
+				}
+			}
+			/* Start afresh after the match if asked */
+			if found && s.NoOverlap {
+				reset()
+				break
+			}
+		}
+		/* Nothing may follow an X */
+		if last {
+			reset()
+		}
+	}
+}
+
+/* runeScanner is what Scan reads from */
+type runeScanner interface {
+	io.RuneScanner
+	io.ByteReader
+}
+
+/* runeReader returns r if it can already read runes, as a bytes.Reader over
+a memory-mapped file can, or r wrapped in a bufio.Reader if not */
+func runeReader(r io.Reader) runeScanner {
+	if rs, ok := r.(runeScanner); ok {
+		return rs
+	}
+	return bufio.NewReader(r)
+}
+
+/* valid returns true if digits has a valid check digit according to a.  The
+Luhn sum of digits is kept in lw as the window slides. */
+func (s *Scanner) valid(a Algorithm, digits []byte, lw *luhnWindow) bool {
+	/* A leading check digit is checked by turning the number around,
+	which needs the Luhn sum working out afresh.  A small number is
+	turned around on the stack. */
+	if s.LeadingCheck {
+		var b [64]byte
+		r := b[:0]
+		var rw luhnWindow
+		for i := len(digits) - 1; 0 <= i; i-- {
+			r = append(r, digits[i])
+			rw.push(digits[i])
+		}
+		digits, lw = r, &rw
+	}
+	/* An X is only a digit to ISBN-10, though other algorithms see it
+	when used alongside it */
+	if ISBN10 != a && s.uses(ISBN10) && 'X' == digits[len(digits)-1] {
+		return false
+	}
+	switch a {
+	case Luhn:
+		return lw.valid()
+	case Mod10:
+		return mod10Valid(digits)
+	case Verhoeff:
+		return verhoeffValid(digits)
+	case Damm:
+		return dammValid(digits)
+	case ISBN10:
+		return isbn10Valid(digits)
+	case IBAN:
+		return ibanValid(digits)
+	case ModN:
+		return modNValid(digits, s.Mod, s.Weights)
+	case LuhnModN:
+		return luhnModN(string(digits), s.alphabet())
+	}
+	return false
+}
+
+/* algorithms returns the algorithms to use, or an error if they can't be used
+together or a modulus or alphabet is missing or invalid */
+func (s *Scanner) algorithms() ([]Algorithm, error) {
+	algs := []Algorithm{s.Algorithm}
+	if 0 != len(s.Algorithms) {
+		algs = s.Algorithms
+	}
+	for _, a := range []Algorithm{IBAN, LuhnModN} {
+		if 1 < len(algs) && s.uses(a) {
+			return nil, fmt.Errorf(
+				"%v can't be used with other algorithms",
+				a,
+			)
+		}
+	}
+	if s.uses(ModN) && 2 > s.Mod {
+		return nil, fmt.Errorf("invalid modulus %v", s.Mod)
+	}
+	if s.LeadingCheck && s.uses(IBAN) {
+		return nil, fmt.Errorf("IBAN can't have a leading check digit")
+	}
+	if s.uses(LuhnModN) {
+		if err := checkAlphabet(s.alphabet()); nil != err {
+			return nil, err
+		}
+	}
+	return algs, nil
+}
+
+/* alphabet returns the alphabet to use with LuhnModN */
+func (s *Scanner) alphabet() string {
+	if "" == s.Alphabet {
+		return DefaultAlphabet
+	}
+	return s.Alphabet
+}
+
+/* uses returns true if a is one of the algorithms in use */
+func (s *Scanner) uses(a Algorithm) bool {
+	if 0 == len(s.Algorithms) {
+		return a == s.Algorithm
+	}
+	for _, v := range s.Algorithms {
+		if a == v {
+			return true
+		}
+	}
+	return false
+}
+
+/* lengths returns the lengths of numbers to find, shortest first */
+func (s *Scanner) lengths() ([]int, error) {
+	lens := []int{s.Len}
+	if 0 != len(s.Lens) {
+		lens = append([]int{}, s.Lens...)
+	} else if 0 == s.Len && s.uses(IBAN) {
+		lens = ibanLens()
+	}
+	sort.Ints(lens)
+	/* Can't find numbers without digits */
+	if 1 > lens[0] {
+		return nil, fmt.Errorf("invalid number length %v", lens[0])
+	}
+	/* No point looking for the same length twice */
+	u := lens[:1]
+	for _, l := range lens[1:] {
+		if u[len(u)-1] != l {
+			u = append(u, l)
+		}
+	}
+	return u, nil
+}
+
+/* digitValue returns the ASCII digit with the same value as the Unicode
+decimal digit c, so that the Arabic-Indic digit four, U+0664, becomes '4'.  If
+c isn't a decimal digit, ok is false. */
+func digitValue(c rune) (d byte, ok bool) {
+	/* Most input will be ASCII */
+	if '0' <= c && '9' >= c {
+		return byte(c), true
+	}
+	if !unicode.IsDigit(c) {
+		return 0, false
+	}
+	/* Unicode puts each set of decimal digits in order, starting with
+	zero, so the digit's value is its distance from the start of its
+	range */
+	for _, r := range unicode.Nd.R16 {
+		if rune(r.Lo) <= c && rune(r.Hi) >= c {
+			return '0' + byte((c-rune(r.Lo))%10), true
+		}
+	}
+	for _, r := range unicode.Nd.R32 {
+		if rune(r.Lo) <= c && rune(r.Hi) >= c {
+			return '0' + byte((c-rune(r.Lo))%10), true
+		}
+	}
+	return 0, false
+}
+
+/* pushRune pushes the UTF-8 encoding of c into r */
+func pushRune(r *ring, c rune) {
+	var b [utf8.UTFMax]byte
+	for _, v := range b[:utf8.EncodeRune(b[:], c)] {
+		r.push(v)
+	}
+}
+
+/* isSep returns true if c is in seps */
+func isSep(c rune, seps string) bool {
+	return strings.ContainsRune(seps, c)
+}
@@ -0,0 +1,67 @@
+/*
+ * testnumbers.go
+ * Well-known test card numbers
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package findcc
+
+/* TestNumbers holds card numbers published by payment processors for testing,
+which turn up in sample code and documentation.  Matches in TestNumbers aren't
+reported by a Scanner with SkipTest set.  More may be added before scanning. */
+var TestNumbers = map[string]bool{
+	/* Visa */
+	"4111111111111111": true,
+	"4012888888881881": true,
+	"4222222222222":    true,
+	"4242424242424242": true,
+	"4000056655665556": true,
+	"4005519200000004": true,
+	"4009348888881881": true,
+	"4012000033330026": true,
+	"4012000077777777": true,
+	"4217651111111119": true,
+	"4500600000000061": true,
+	/* Mastercard */
+	"5555555555554444": true,
+	"5105105105105100": true,
+	"5454545454545454": true,
+	"2223003122003222": true,
+	"5200828282828210": true,
+	/* Amex */
+	"378282246310005": true,
+	"371449635398431": true,
+	"378734493671000": true,
+	"340000000000009": true,
+	"370000000000002": true,
+	/* Discover */
+	"6011111111111117": true,
+	"6011000990139424": true,
+	"6011000400000000": true,
+	"6011601160116611": true,
+	/* Diners */
+	"30569309025904":   true,
+	"38520000023237":   true,
+	"3056930009020004": true,
+	"36227206271667":   true,
+	/* JCB */
+	"3530111333300000": true,
+	"3566002020360505": true,
+	"3566111111111113": true,
+	/* UnionPay */
+	"6200000000000005": true,
+}
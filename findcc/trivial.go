@@ -0,0 +1,69 @@
+/*
+ * trivial.go
+ * Spot numbers too regular to be real
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package findcc
+
+/* looksTrivial returns true if the number n, including its check digit, is too
+regular to be real data, even though it's valid.  A number is trivial if it has
+fewer different digits than a quarter of its length, as with 4000000000000002;
+if the digits before the check digit go up or down by one each time, wrapping
+between 9 and 0, as with 1234567890123452; or if the digits before the check
+digit are a block repeated at least twice, the last repeat perhaps cut short by
+the check digit, as with 1234567812345670.  Numbers of fewer than four digits
+are never trivial. */
+func looksTrivial(n []byte) bool {
+	if 4 > len(n) {
+		return false
+	}
+	/* Too few different digits */
+	var seen [256]bool
+	ndistinct := 0
+	for _, c := range n {
+		if !seen[c] {
+			seen[c] = true
+			ndistinct++
+		}
+	}
+	if 4*ndistinct < len(n) {
+		return true
+	}
+	/* Counting up or down, ignoring the check digit */
+	body := n[:len(n)-1]
+	up, down := true, true
+	for i := 1; i < len(body); i++ {
+		d := (int(body[i]) - int(body[i-1]) + 10) % 10
+		up = up && 1 == d
+		down = down && 9 == d
+	}
+	if up || down {
+		return true
+	}
+	/* A repeated block, ignoring the check digit */
+	for p := 1; 2*p-1 <= len(body); p++ {
+		rep := true
+		for i := p; i < len(body) && rep; i++ {
+			rep = body[i] == body[i-p]
+		}
+		if rep {
+			return true
+		}
+	}
+	return false
+}
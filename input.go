@@ -0,0 +1,184 @@
+/*
+ * input.go
+ * Prepare input for scanning
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+/* gzipMagic starts every gzip stream */
+const gzipMagic = "\x1f\x8b"
+
+/* With -text, a file is binary if the first binaryProbe bytes of it have a NUL
+in them or more than binaryPercent percent of them aren't text */
+const (
+	binaryProbe   = 8 * 1024
+	binaryPercent = 30
+)
+
+/* followWait is how long to wait for more to be written to a followed file */
+const followWait = 250 * time.Millisecond
+
+/* gunzip returns a reader which decompresses r if it starts with gzip's magic
+number, or always if force is true.  Otherwise, r's contents are returned
+unchanged. */
+func gunzip(r io.Reader, force bool) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if !force {
+		magic, err := br.Peek(len(gzipMagic))
+		/* Too short to be gzipped */
+		if io.EOF == err {
+			return br, nil
+		}
+		if nil != err {
+			return nil, err
+		}
+		if gzipMagic != string(magic) {
+			return br, nil
+		}
+	}
+	return gzip.NewReader(br)
+}
+
+/* gzipped returns true if r starts with gzip's magic number */
+func gzipped(r io.ReaderAt) bool {
+	magic := make([]byte, len(gzipMagic))
+	n, _ := r.ReadAt(magic, 0)
+	return gzipMagic == string(magic[:n])
+}
+
+/* isBinary returns true if b, the start of a file, looks binary.  Control
+characters other than whitespace and backspace aren't text, and nor are bytes
+which aren't valid UTF-8, though a character cut off at the end of b is. */
+func isBinary(b []byte) bool {
+	if 0 == len(b) {
+		return false
+	}
+	nbin := 0 /* Bytes which aren't text */
+	for i := 0; i < len(b); {
+		c, size := utf8.DecodeRune(b[i:])
+		/* Only the end's allowed to be cut off */
+		if utf8.RuneError == c && 1 == size && !utf8.FullRune(b[i:]) {
+			break
+		}
+		switch {
+		case 0 == c:
+			return true
+		case utf8.RuneError == c && 1 == size, 0x7f == c:
+			nbin++
+		case ' ' > c && !strings.ContainsRune("\b\t\n\v\f\r", c):
+			nbin++
+		}
+		i += size
+	}
+	return nbin*100 > len(b)*binaryPercent
+}
+
+/* probeText reads the start of r to see if it's binary.  It returns a reader
+which reads all of r, and whether r looked like text. */
+func probeText(r io.Reader) (io.Reader, bool, error) {
+	br := bufio.NewReaderSize(r, binaryProbe)
+	b, err := br.Peek(binaryProbe)
+	if nil != err && io.EOF != err {
+		return nil, false, err
+	}
+	return br, !isBinary(b), nil
+}
+
+/* follower reads a file like tail -f.  At the end of the file it waits for
+more to be written, and if the file is truncated or replaced it starts again
+at the beginning of the new file.  Once stop is closed, it returns io.EOF
+instead of waiting. */
+type follower struct {
+	name string          /* Name of the file */
+	f    *os.File        /* File being read */
+	pos  int64           /* Bytes read from f */
+	stop <-chan struct{} /* Closed to stop following */
+}
+
+/* Read reads from the file, waiting for more if there's none */
+func (f *follower) Read(p []byte) (int, error) {
+	for {
+		n, err := f.f.Read(p)
+		f.pos += int64(n)
+		if 0 != n || io.EOF != err {
+			return n, err
+		}
+		/* Start the new file straight away */
+		if f.reopen() {
+			continue
+		}
+		select {
+		case <-f.stop:
+			return 0, io.EOF
+		case <-time.After(followWait):
+		}
+	}
+}
+
+/* reopen opens the file again if it's been truncated or replaced, and
+returns true if it did */
+func (f *follower) reopen() bool {
+	fi, err := os.Stat(f.name)
+	if nil != err {
+		return false
+	}
+	cur, err := f.f.Stat()
+	if nil != err {
+		return false
+	}
+	if os.SameFile(fi, cur) && fi.Size() >= f.pos {
+		return false
+	}
+	nf, err := os.Open(f.name)
+	if nil != err {
+		return false
+	}
+	f.f.Close()
+	f.f, f.pos = nf, 0
+	return true
+}
+
+/* Close closes the file being read */
+func (f *follower) Close() error {
+	return f.f.Close()
+}
+
+/* countReader counts the bytes read from r in n, atomically so it may be read
+while scanning */
+type countReader struct {
+	r io.Reader
+	n *int64
+}
+
+/* Read reads from r and counts what was read */
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	atomic.AddInt64(c.n, int64(n))
+	return n, err
+}
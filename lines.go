@@ -0,0 +1,257 @@
+/*
+ * lines.go
+ * Print the lines around matches, like grep -A, -B and -C
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/kd5pbo/findcc/findcc"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+/* maxLineText is how much of a line is kept to be printed */
+const maxLineText = 4096
+
+/* lineContext prints the lines around matches, like grep -A, -B and -C.  It
+sits between the input and the scanner and hands the scanner a line at a time.
+The scanner only asks for more once it's used up what it has, so when a new
+line is asked for, no more matches can be found on the lines before it.  Lines
+are printed once it's known whether they're near a match.  Groups of lines are
+separated by --, and when matches are close enough that their groups would
+overlap, the groups are merged and each line is printed once. */
+type lineContext struct {
+	r      *bufio.Reader /* Input */
+	before int           /* Lines to print before each match */
+	after  int           /* Lines to print after each match */
+	mask   bool          /* Mask the matched numbers */
+	prefix string        /* Printed first on each line */
+	w      io.Writer     /* Output */
+	mu     *sync.Mutex   /* Held while printing */
+
+	cur   []byte /* Input not yet given to the scanner */
+	rerr  error  /* Error to return once cur is used up */
+	text  []byte /* Line being read, up to maxLineText bytes */
+	start int    /* Offset of the line being read */
+	nread int    /* Bytes read from r */
+	nline int    /* Number of the line being read */
+
+	lines     []string               /* Lines read but not printed */
+	starts    []int                  /* Offset of each of lines */
+	first     int                    /* Number of lines[0] */
+	matches   map[int][]findcc.Match /* Matches on lines not printed */
+	lastMatch int                    /* Last line with a match, or -1 */
+	printed   int                    /* Last line printed, or -1 */
+	err       error                  /* Error printing */
+}
+
+/* newLineContext returns a lineContext which reads from r and prints to w */
+func newLineContext(
+	r io.Reader,
+	w io.Writer,
+	mu *sync.Mutex,
+	before, after int,
+	mask bool,
+	prefix string,
+) *lineContext {
+	return &lineContext{
+		r:         bufio.NewReader(r),
+		before:    before,
+		after:     after,
+		mask:      mask,
+		prefix:    prefix,
+		w:         w,
+		mu:        mu,
+		matches:   map[int][]findcc.Match{},
+		lastMatch: -1,
+		printed:   -1,
+	}
+}
+
+/* Read gives the scanner what's left of the current line, reading more if
+there's nothing left */
+func (c *lineContext) Read(p []byte) (int, error) {
+	if 0 == len(c.cur) {
+		if nil != c.rerr {
+			return 0, c.rerr
+		}
+		/* Starting a new line means the scanner's done with the
+		last one */
+		if 0 == len(c.text) {
+			c.mu.Lock()
+			c.decide(c.nline - 1)
+			c.mu.Unlock()
+		}
+		c.cur, c.rerr = readLine(c.r)
+		c.nread += len(c.cur)
+		/* Keep as much of the line as we can */
+		t := c.cur
+		if room := maxLineText - len(c.text); len(t) > room {
+			t = t[:room]
+		}
+		c.text = append(c.text, t...)
+		/* Note the line if it's ended, or if it's the last one */
+		if (0 != len(c.cur) && isLineEnd(c.cur[len(c.cur)-1])) ||
+			(nil != c.rerr && 0 != len(c.text)) {
+			c.lines = append(c.lines, string(c.text))
+			c.starts = append(c.starts, c.start)
+			c.text = c.text[:0]
+			c.start = c.nread
+			c.nline++
+		}
+		if 0 == len(c.cur) {
+			return 0, c.rerr
+		}
+	}
+	n := copy(p, c.cur)
+	c.cur = c.cur[n:]
+	return n, nil
+}
+
+/* readLine returns what's buffered in r of the current line, including the
+line break if it's there, reading more if nothing's buffered.  Like the
+scanner, it ends lines with \n, \r or \r\n.  The returned slice is only
+valid until the next read from r. */
+func readLine(r *bufio.Reader) ([]byte, error) {
+	if _, err := r.Peek(1); nil != err {
+		return nil, err
+	}
+	b, _ := r.Peek(r.Buffered())
+	i := bytes.IndexAny(b, "\r\n")
+	switch {
+	case -1 == i:
+		return take(r, len(b)), nil
+	case '\n' == b[i]:
+		return take(r, i+1), nil
+	}
+	/* A \r may have a \n after it, which might not be read yet.  If
+	there's no room to read it, the \r waits for next time. */
+	if i+1 == len(b) {
+		if i+2 > r.Size() {
+			return take(r, i), nil
+		}
+		b, _ = r.Peek(i + 2)
+	}
+	if i+1 < len(b) && '\n' == b[i+1] {
+		i++
+	}
+	return take(r, i+1), nil
+}
+
+/* take returns the next n bytes buffered in r and skips past them.  The
+returned slice is only valid until the next read from r. */
+func take(r *bufio.Reader, n int) []byte {
+	b, _ := r.Peek(n)
+	r.Discard(n)
+	return b
+}
+
+/* isLineEnd returns true if c ends a line */
+func isLineEnd(c byte) bool {
+	return '\n' == c || '\r' == c
+}
+
+/* match notes a match, to be printed with its line */
+func (c *lineContext) match(m findcc.Match) {
+	c.matches[m.Line] = append(c.matches[m.Line], m)
+}
+
+/* decide prints or forgets each line read so far which either needs to be
+printed or can't be near a match.  No more matches will be found on line
+last or any before it. */
+func (c *lineContext) decide(last int) {
+	for 0 != len(c.lines) && nil == c.err {
+		i := c.first
+		ms := c.matches[i]
+		show := 0 != len(ms) ||
+			(0 <= c.lastMatch && i-c.lastMatch <= c.after)
+		/* Lines shortly before a match are printed, if we know
+		whether there's a match after them */
+		for j := i + 1; !show && j <= i+c.before; j++ {
+			if j > last {
+				return
+			}
+			show = 0 != len(c.matches[j])
+		}
+		if show {
+			c.print(i, ms)
+		}
+		if 0 != len(ms) {
+			c.lastMatch = i
+			delete(c.matches, i)
+		}
+		c.lines = c.lines[1:]
+		c.starts = c.starts[1:]
+		c.first++
+	}
+}
+
+/* print prints line i, which has the matches in ms */
+func (c *lineContext) print(i int, ms []findcc.Match) {
+	/* Separate groups of lines like grep */
+	if 0 <= c.printed && i-1 != c.printed {
+		if _, c.err = fmt.Fprintf(c.w, "--\n"); nil != c.err {
+			return
+		}
+	}
+	c.printed = i
+	sep := '-'
+	if 0 != len(ms) {
+		sep = ':'
+	}
+	text := strings.TrimRight(c.lines[0], "\r\n")
+	if c.mask {
+		text = maskLine(text, c.starts[0], ms)
+	}
+	_, c.err = fmt.Fprintf(c.w, "%v%v%c%v\n", c.prefix, i, sep, text)
+}
+
+/* maskLine masks the numbers in ms in text, which is the line starting at
+offset start */
+func maskLine(text string, start int, ms []findcc.Match) string {
+	/* Work out which bytes to hide */
+	hide := make([]bool, len(text))
+	for _, m := range ms {
+		o := m.Offset - start
+		masked := []rune(findcc.Mask(m.Raw))
+		for i, r := range []rune(m.Raw) {
+			if masked[i] != r && o < len(hide) {
+				hide[o] = true
+			}
+			o += utf8.RuneLen(r)
+		}
+	}
+	/* Replace each hidden character with a * */
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		if hide[i] {
+			b.WriteByte('*')
+		} else {
+			b.WriteString(text[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
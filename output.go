@@ -0,0 +1,260 @@
+/*
+ * output.go
+ * Print matches as a table, JSON, or CSV
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/kd5pbo/findcc/findcc"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+/* ANSI escape sequences to start and end highlighting */
+const (
+	colorStart = "\x1b[1;31m"
+	colorEnd   = "\x1b[0m"
+)
+
+/* column is one field of output */
+type column struct {
+	name     string                           /* Name, for the header */
+	width    int                              /* Width in the table */
+	jsonOnly bool                             /* Only print in JSON */
+	color    bool                             /* Highlight in the table */
+	val      func(m findcc.Match) interface{} /* Value for a match */
+}
+
+/* printer prints matches as a table, one JSON object per line, CSV,
+NUL-terminated records, or with a template.  Strings are always quoted in CSV,
+so numbers aren't mistaken for integers.  NUL-terminated records have no
+header, and their fields are separated by tabs, without padding.  A template is
+given a formatRecord for each match, and what it produces ends with a newline,
+or a NUL with nul set, with no header. */
+type printer struct {
+	w        io.Writer          /* Output */
+	json     bool               /* Print JSON */
+	csv      bool               /* Print CSV */
+	nul      bool               /* Print NUL-terminated records */
+	tmpl     *template.Template /* Template for each match */
+	mask     bool               /* Mask numbers, for the template */
+	showName bool               /* Print the name of the input */
+	color    bool               /* Highlight columns in the table */
+	cols     []column           /* Fields to print */
+}
+
+/* header prints the header, unless we're printing JSON or NUL-terminated
+records */
+func (p *printer) header() error {
+	/* JSON has no header, and nor do records for xargs -0 or
+	templates */
+	if p.json || p.nul || (nil != p.tmpl && !p.csv) {
+		return nil
+	}
+	/* CSV's header comes from encoding/csv */
+	if p.csv {
+		hdr := []string{}
+		if p.showName {
+			hdr = append(hdr, "file")
+		}
+		for _, c := range p.cols {
+			if !c.jsonOnly {
+				hdr = append(hdr, c.name)
+			}
+		}
+		w := csv.NewWriter(p.w)
+		w.UseCRLF = true
+		w.Write(hdr)
+		w.Flush()
+		return w.Error()
+	}
+	/* The table's is the names, in uppercase */
+	hdr := []string{}
+	for _, c := range p.cols {
+		if !c.jsonOnly {
+			hdr = append(hdr, fmt.Sprintf("%*v", c.width,
+				strings.ToUpper(c.name)))
+		}
+	}
+	_, err := fmt.Fprintf(p.w, "%v\n", strings.Join(hdr, "  "))
+	return err
+}
+
+/* print prints m, which was found in the input called name */
+func (p *printer) print(name string, m findcc.Match) error {
+	/* JSON is built by hand to keep the fields in order */
+	if p.json {
+		fs := []string{}
+		if p.showName {
+			fs = append(fs, jsonField("file", name))
+		}
+		for _, c := range p.cols {
+			fs = append(fs, jsonField(c.name, c.val(m)))
+		}
+		_, err := fmt.Fprintf(p.w, "{%v}\n", strings.Join(fs, ","))
+		return err
+	}
+	fs := []string{}
+	/* CSV, quoting strings by hand as encoding/csv never quotes digits */
+	if p.csv {
+		if p.showName {
+			fs = append(fs, csvQuote(name))
+		}
+		for _, c := range p.cols {
+			if c.jsonOnly {
+				continue
+			}
+			v := c.val(m)
+			if s, ok := v.(string); ok {
+				fs = append(fs, csvQuote(s))
+			} else {
+				fs = append(fs, fmt.Sprintf("%v", v))
+			}
+		}
+		_, err := fmt.Fprintf(p.w, "%v\r\n", strings.Join(fs, ","))
+		return err
+	}
+	/* Whatever the template says */
+	if nil != p.tmpl {
+		if err := p.tmpl.Execute(
+			p.w,
+			newFormatRecord(name, m, p.mask),
+		); nil != err {
+			return err
+		}
+		end := "\n"
+		if p.nul {
+			end = "\x00"
+		}
+		_, err := io.WriteString(p.w, end)
+		return err
+	}
+	/* NUL-terminated records, with tabs between the fields */
+	if p.nul {
+		if p.showName {
+			fs = append(fs, name)
+		}
+		for _, c := range p.cols {
+			if !c.jsonOnly {
+				fs = append(fs, fmt.Sprintf("%v", c.val(m)))
+			}
+		}
+		_, err := fmt.Fprintf(p.w, "%v\x00", strings.Join(fs, "\t"))
+		return err
+	}
+	/* Table, with the filename prefixed like grep.  Highlighting goes
+	around the padding, so it doesn't upset the alignment. */
+	for _, c := range p.cols {
+		if c.jsonOnly {
+			continue
+		}
+		f := fmt.Sprintf("%*v", c.width, c.val(m))
+		if p.color && c.color {
+			f = colorStart + f + colorEnd
+		}
+		fs = append(fs, f)
+	}
+	pre := ""
+	if p.showName {
+		pre = name + ":"
+	}
+	_, err := fmt.Fprintf(p.w, "%v%v\n", pre, strings.Join(fs, "  "))
+	return err
+}
+
+/* formatRecord is what a -format template is given for each match */
+type formatRecord struct {
+	File      string /* Name of the input */
+	Offset    int    /* Offset of the first digit */
+	Line      int    /* Line number, counting from 0 */
+	Column    int    /* Column, counting from 1 */
+	Number    string /* The number, masked with -mask */
+	Raw       string /* The number as it appeared, masked with -mask */
+	Length    int    /* Length of the number */
+	Brand     string /* Card brand, or unknown */
+	Algorithm string /* Check digit algorithm */
+	Before    string /* Context before the number, with -context */
+	After     string /* Context after the number, with -context */
+}
+
+/* newFormatRecord returns the formatRecord for m, found in name.  If mask is
+true, the number and context are masked. */
+func newFormatRecord(name string, m findcc.Match, mask bool) formatRecord {
+	r := formatRecord{
+		File:      name,
+		Offset:    m.Offset,
+		Line:      m.Line,
+		Column:    m.Column,
+		Number:    m.Number,
+		Raw:       m.Raw,
+		Length:    len(m.Number),
+		Brand:     findcc.Brand(m.Number),
+		Algorithm: m.Algorithm.String(),
+		Before:    printable(m.Before, mask),
+		After:     printable(m.After, mask),
+	}
+	if mask {
+		r.Number = findcc.Mask(m.Number)
+		r.Raw = findcc.Mask(m.Raw)
+	}
+	return r
+}
+
+/* jsonField returns a "key":value pair for a JSON object */
+func jsonField(k string, v interface{}) string {
+	kb, _ := json.Marshal(k)
+	vb, _ := json.Marshal(v)
+	return string(kb) + ":" + string(vb)
+}
+
+/* csvQuote quotes s as a CSV field, whether or not it needs it */
+func csvQuote(s string) string {
+	return `"` + strings.Replace(s, `"`, `""`, -1) + `"`
+}
+
+/* printable replaces the bytes in s which aren't printable ASCII with dots,
+for printing context.  If mask is true, digits are replaced with *s, so that
+context doesn't give away other numbers. */
+func printable(s string, mask bool) string {
+	b := []byte(s)
+	for i, c := range b {
+		switch {
+		case mask && '0' <= c && '9' >= c:
+			b[i] = '*'
+		case ' ' > c || '~' < c:
+			b[i] = '.'
+		}
+	}
+	return string(b)
+}
+
+/* isTerminal returns true if f is a terminal, or at least a character device,
+which is close enough to decide whether to use color */
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if nil != err {
+		return false
+	}
+	return 0 != fi.Mode()&os.ModeCharDevice
+}
@@ -0,0 +1,95 @@
+/*
+ * stats.go
+ * Count what was found, for -stats
+ * by J. Stuart McMurray
+ * created 20261014
+ * last modified 20261014
+ * Copyright (c) 2026 J. Stuart McMurray <kd5pbo@gmail.com>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package main
+
+import (
+	"fmt"
+	"github.com/kd5pbo/findcc/findcc"
+	"io"
+	"sort"
+	"strings"
+)
+
+/* stats counts the matches found in every input, for -stats.  It's not safe
+for concurrent use; callers hold the lock used for printing matches. */
+type stats struct {
+	brand   bool                /* Break down by brand, not length */
+	matches int                 /* Matches found */
+	seen    map[string]struct{} /* Different numbers found */
+	byBrand map[string]int      /* Matches by brand, with brand */
+	byLen   map[int]int         /* Matches by length, without brand */
+}
+
+/* newStats returns a stats which breaks matches down by brand if brand is
+true, or by length otherwise */
+func newStats(brand bool) *stats {
+	return &stats{
+		brand:   brand,
+		seen:    make(map[string]struct{}),
+		byBrand: make(map[string]int),
+		byLen:   make(map[int]int),
+	}
+}
+
+/* add counts m */
+func (s *stats) add(m findcc.Match) {
+	s.matches++
+	s.seen[m.Number] = struct{}{}
+	if s.brand {
+		s.byBrand[findcc.Brand(m.Number)]++
+	} else {
+		s.byLen[len(m.Number)]++
+	}
+}
+
+/* print prints a line saying how many bytes were scanned and what was found
+in them, with the brands or lengths in order */
+func (s *stats) print(w io.Writer, nbytes int64) error {
+	parts := []string{}
+	if s.brand {
+		names := []string{}
+		for b := range s.byBrand {
+			names = append(names, b)
+		}
+		sort.Strings(names)
+		for _, b := range names {
+			parts = append(parts, fmt.Sprintf("%v: %v", b,
+				s.byBrand[b]))
+		}
+	} else {
+		lens := []int{}
+		for l := range s.byLen {
+			lens = append(lens, l)
+		}
+		sort.Ints(lens)
+		for _, l := range lens {
+			parts = append(parts, fmt.Sprintf("length %v: %v", l,
+				s.byLen[l]))
+		}
+	}
+	by := ""
+	if 0 != len(parts) {
+		by = "; " + strings.Join(parts, ", ")
+	}
+	_, err := fmt.Fprintf(w, "Scanned %v bytes and found %v matches, "+
+		"%v different%v.\n", nbytes, s.matches, len(s.seen), by)
+	return err
+}